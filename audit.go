@@ -0,0 +1,233 @@
+package swarm
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+// redacted replaces the values of secrets in audit entries.
+const redacted = "[REDACTED]"
+
+// secretKeys are the (lower case) parts of parameter names whose values are
+// redacted in audit entries.
+var secretKeys = []string{"password", "passwd", "secret", "token", "ticket"}
+
+// AuditEntry records a single mutating request made by the client.
+type AuditEntry struct {
+	Time time.Time `json:"time"`
+
+	// Actor is the user the client is authenticated as, Sudo the user the
+	// request was made on behalf of, if any.
+	Actor string `json:"actor,omitempty"`
+	Sudo  string `json:"sudo,omitempty"`
+
+	Method   string `json:"method"`
+	Endpoint string `json:"endpoint"`
+	URL      string `json:"url"`
+
+	// Body is the encoded request body with secrets redacted.
+	Body string `json:"body,omitempty"`
+
+	StatusCode int    `json:"status,omitempty"`
+	Error      string `json:"error,omitempty"`
+	DryRun     bool   `json:"dryRun,omitempty"`
+
+	// Before and After are snapshots of the changed object, e.g. a Project or
+	// Workflow, when known by the service method.
+	Before interface{} `json:"before,omitempty"`
+	After  interface{} `json:"after,omitempty"`
+}
+
+// AuditSink receives an AuditEntry for every mutating request made through
+// Client.Do, after the request finished.
+type AuditSink interface {
+	Audit(entry *AuditEntry)
+}
+
+// JSONLinesSink is an AuditSink writing each entry as a line of JSON.
+type JSONLinesSink struct {
+	mu  sync.Mutex
+	w   io.Writer
+	err error
+}
+
+// NewJSONLinesSink returns a sink writing to w.
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	return &JSONLinesSink{w: w}
+}
+
+// NewJSONLinesFileSink returns a sink appending to the file at path, the file
+// is created if it does not exist.
+func NewJSONLinesFileSink(path string) (*JSONLinesSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return NewJSONLinesSink(f), nil
+}
+
+// Audit writes entry to the sink.
+func (s *JSONLinesSink) Audit(entry *AuditEntry) {
+	line, err := json.Marshal(entry)
+	if err == nil {
+		line = append(line, '\n')
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err == nil {
+		_, err = s.w.Write(line)
+	}
+	if err != nil && s.err == nil {
+		s.err = err
+	}
+}
+
+// Err returns the first error which occurred while writing entries.
+func (s *JSONLinesSink) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close closes the underlying writer, if it is an io.Closer.
+func (s *JSONLinesSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// withAudit prepends an option adding before/after snapshots to the audit
+// entry of the request. after is called once the response is decoded.
+func withAudit(before interface{}, after func() interface{}, options []RequestOptionFunc) []RequestOptionFunc {
+	return append([]RequestOptionFunc{withRequestConfig(func(cfg *requestConfig) {
+		if before != nil {
+			cfg.auditBefore = before
+		}
+		if after != nil {
+			cfg.auditAfter = after
+		}
+	})}, options...)
+}
+
+// audit sends the request and records it in the audit sink.
+func (c *Client) audit(req *retryablehttp.Request, v interface{}) (*Response, error) {
+	cfg := requestConfigFrom(req.Context())
+	entry := &AuditEntry{
+		Time:     time.Now(),
+		Actor:    c.username,
+		Sudo:     req.Header.Get("SUDO"),
+		Method:   req.Method,
+		Endpoint: c.endpoint(req),
+		URL:      redactURL(req.URL),
+		Before:   cfg.auditBefore,
+	}
+	if body, err := req.BodyBytes(); err == nil {
+		entry.Body = redactBody(req.Header.Get("Content-Type"), body)
+	}
+
+	resp, err := c.dispatch(req, v)
+
+	if resp != nil {
+		entry.StatusCode = resp.StatusCode
+		entry.DryRun = resp.DryRun != nil
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	} else if cfg.auditAfter != nil && !entry.DryRun {
+		// Don't record typed nil pointers, e.g. when Swarm returned no object.
+		if after := reflect.ValueOf(cfg.auditAfter()); after.IsValid() && !(after.Kind() == reflect.Ptr && after.IsNil()) {
+			entry.After = after.Interface()
+		}
+	}
+	c.auditSink.Audit(entry)
+
+	return resp, err
+}
+
+// isSecret reports whether the parameter name refers to a secret.
+func isSecret(name string) bool {
+	name = strings.ToLower(name)
+	for _, key := range secretKeys {
+		if strings.Contains(name, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL returns u with the values of secret query parameters redacted.
+func redactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+	redactedURL := *u
+	redactedURL.RawQuery = redactValues(u.Query()).Encode()
+	return redactedURL.String()
+}
+
+func redactValues(values url.Values) url.Values {
+	for name := range values {
+		if isSecret(name) {
+			values[name] = []string{redacted}
+		}
+	}
+	return values
+}
+
+// redactBody redacts secrets in form encoded and JSON bodies.
+func redactBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return redacted
+		}
+		return redactValues(values).Encode()
+	case "application/json":
+		var v interface{}
+		if err := json.Unmarshal(body, &v); err != nil {
+			return redacted
+		}
+		b, err := json.Marshal(redactJSON(v))
+		if err != nil {
+			return redacted
+		}
+		return string(b)
+	}
+	return string(body)
+}
+
+func redactJSON(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for name, value := range v {
+			if isSecret(name) {
+				v[name] = redacted
+			} else {
+				v[name] = redactJSON(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactJSON(value)
+		}
+	}
+	return v
+}
@@ -0,0 +1,129 @@
+package swarm
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// decodeAuditEntries decodes the JSON lines written by a JSONLinesSink.
+func decodeAuditEntries(b []byte) []map[string]interface{} {
+	var entries []map[string]interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	for scanner.Scan() {
+		var entry map[string]interface{}
+		So(json.Unmarshal(scanner.Bytes(), &entry), ShouldBeNil)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAudit_AddMembers(t *testing.T) {
+	Convey("test audit of AddMembers", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var buf bytes.Buffer
+		sink := NewJSONLinesSink(&buf)
+		So(WithAuditSink(sink)(client), ShouldBeNil)
+
+		var patched url.Values
+		mux.HandleFunc("/api/v9/projects/got-dev", handleMembersProject(t, &patched))
+
+		_, _, err := client.Projects.AddMembers("got-dev", []string{"tangyq"}, WithSudo("admin"))
+		So(err, ShouldBeNil)
+		So(sink.Err(), ShouldBeNil)
+
+		entries := decodeAuditEntries(buf.Bytes())
+		So(entries, ShouldHaveLength, 1)
+
+		entry := entries[0]
+		So(entry["actor"], ShouldEqual, "username")
+		So(entry["sudo"], ShouldEqual, "admin")
+		So(entry["method"], ShouldEqual, http.MethodPatch)
+		So(entry["endpoint"], ShouldEqual, "projects/{id}")
+		So(entry["url"], ShouldEqual, server.URL+"/api/v9/projects/got-dev")
+		So(entry["body"], ShouldEqual, "members%5B%5D=eyotang&members%5B%5D=swarm&members%5B%5D=tangyq")
+		So(entry["status"], ShouldEqual, http.StatusOK)
+		So(entry["before"].(map[string]interface{})["members"], ShouldResemble, []interface{}{"eyotang", "swarm"})
+		So(entry["after"].(map[string]interface{})["id"], ShouldEqual, "got-dev")
+	})
+}
+
+func TestAudit_SetGlobalExclusions(t *testing.T) {
+	Convey("test audit of SetGlobalExclusions", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var buf bytes.Buffer
+		So(WithAuditSink(NewJSONLinesSink(&buf))(client), ShouldBeNil)
+
+		mux.HandleFunc("/api/v9/workflows/0", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprint(w, `{"workflow": {"id": 0, "name": "Global Workflow", "description": "global", "user_exclusions": {"rule": ["old"]}}}`)
+		})
+		mux.HandleFunc("/api/v10/workflows/0", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPut)
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error": "Forbidden"}`)
+		})
+
+		err := client.Workflows.SetGlobalExclusions(nil, []string{"bot"})
+		So(err, ShouldNotBeNil)
+
+		entries := decodeAuditEntries(buf.Bytes())
+		So(entries, ShouldHaveLength, 1)
+		So(entries[0]["endpoint"], ShouldEqual, "api/v10/workflows/{id}")
+		So(entries[0]["status"], ShouldEqual, http.StatusForbidden)
+		So(entries[0]["error"], ShouldNotBeEmpty)
+		So(entries[0]["before"].(map[string]interface{})["user_exclusions"], ShouldResemble, map[string]interface{}{"rule": []interface{}{"old"}, "mode": ""})
+		So(entries[0], ShouldNotContainKey, "after")
+	})
+}
+
+func TestAudit_Redaction(t *testing.T) {
+	Convey("test redaction of audit entries", t, func() {
+		So(redactBody("application/x-www-form-urlencoded", []byte("name=a&password=x&api_token=y")), ShouldEqual,
+			"api_token=%5BREDACTED%5D&name=a&password=%5BREDACTED%5D")
+		So(redactBody("application/json; charset=utf-8", []byte(`{"name":"a","nested":[{"Secret":"x"}],"ticket":"y"}`)), ShouldEqual,
+			`{"name":"a","nested":[{"Secret":"[REDACTED]"}],"ticket":"[REDACTED]"}`)
+		So(redactBody("application/json", []byte(`not json`)), ShouldEqual, redacted)
+		So(redactBody("", nil), ShouldEqual, "")
+
+		u, err := url.Parse("https://swarm.url/api/v9/projects?token=abc&fields=id")
+		So(err, ShouldBeNil)
+		So(redactURL(u), ShouldEqual, "https://swarm.url/api/v9/projects?fields=id&token=%5BREDACTED%5D")
+	})
+}
+
+func TestJSONLinesFileSink(t *testing.T) {
+	Convey("test JSON lines file sink", t, func() {
+		dir, err := ioutil.TempDir("", "swarm-audit")
+		So(err, ShouldBeNil)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "audit.jsonl")
+
+		for i := 0; i < 2; i++ {
+			sink, err := NewJSONLinesFileSink(path)
+			So(err, ShouldBeNil)
+			sink.Audit(&AuditEntry{Method: http.MethodDelete, Endpoint: "projects/{id}"})
+			So(sink.Err(), ShouldBeNil)
+			So(sink.Close(), ShouldBeNil)
+		}
+
+		b, err := ioutil.ReadFile(path)
+		So(err, ShouldBeNil)
+		entries := decodeAuditEntries(b)
+		So(entries, ShouldHaveLength, 2)
+		So(entries[1]["method"], ShouldEqual, http.MethodDelete)
+	})
+}
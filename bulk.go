@@ -0,0 +1,157 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// defaultBulkConcurrency is the number of operations run in parallel when no
+// concurrency is configured.
+const defaultBulkConcurrency = 4
+
+// ErrBulkSkipped is the error of operations which were not run, because an
+// earlier operation failed while stopping on the first error.
+var ErrBulkSkipped = errors.New("operation skipped after an earlier error")
+
+// BulkFunc is a single operation of a bulk run. It should pass ctx to the
+// service call, e.g. by using the Ctx variant of a method, so it is canceled
+// when the run stops.
+type BulkFunc func(ctx context.Context) (interface{}, *Response, error)
+
+// BulkOptions configures a bulk run.
+type BulkOptions struct {
+	// Concurrency is the maximum number of operations running at the same
+	// time. Defaults to 4.
+	Concurrency int
+
+	// StopOnError stops the run on the first failing operation. Operations
+	// which did not start yet are skipped and running ones are canceled.
+	StopOnError bool
+
+	// Progress is called after each finished operation. Calls are never made
+	// concurrently.
+	Progress func(BulkProgress)
+}
+
+// BulkProgress reports the progress of a bulk run.
+type BulkProgress struct {
+	Total   int
+	Done    int
+	Failed  int
+	Skipped int
+
+	// Result is the result of the operation which just finished.
+	Result *BulkResult
+}
+
+// BulkResult is the result of a single operation of a bulk run.
+type BulkResult struct {
+	// Index is the position of the operation in the list passed to Bulk.
+	Index    int
+	Value    interface{}
+	Response *Response
+	Err      error
+}
+
+// BulkError is returned by Bulk when one or more operations failed.
+type BulkError struct {
+	Total  int
+	Failed []*BulkResult
+}
+
+func (e *BulkError) Error() string {
+	return fmt.Sprintf("%d of %d operations failed, first error: %v", len(e.Failed), e.Total, e.Failed[0].Err)
+}
+
+// Unwrap returns the error of the first failed operation.
+func (e *BulkError) Unwrap() error {
+	return e.Failed[0].Err
+}
+
+// Bulk runs many operations with bounded concurrency. All operations share
+// the rate limiter of the client, as every request goes through Do. The
+// returned results are in the same order as ops. A *BulkError is returned
+// when any operation failed.
+func (c *Client) Bulk(ctx context.Context, ops []BulkFunc, opt *BulkOptions) ([]*BulkResult, error) {
+	if opt == nil {
+		opt = &BulkOptions{}
+	}
+	concurrency := opt.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBulkConcurrency
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]*BulkResult, len(ops))
+	progress := BulkProgress{Total: len(ops)}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		stopped bool
+	)
+	finish := func(r *BulkResult) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		results[r.Index] = r
+		progress.Done++
+		switch {
+		case r.Err == ErrBulkSkipped:
+			progress.Skipped++
+		case r.Err != nil:
+			progress.Failed++
+			if opt.StopOnError && !stopped {
+				stopped = true
+				cancel()
+			}
+		}
+		if opt.Progress != nil {
+			progress.Result = r
+			opt.Progress(progress)
+		}
+	}
+	isStopped := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return stopped
+	}
+
+	sem := make(chan struct{}, concurrency)
+	for i, op := range ops {
+		sem <- struct{}{}
+		if isStopped() {
+			<-sem
+			finish(&BulkResult{Index: i, Err: ErrBulkSkipped})
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, op BulkFunc) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			value, resp, err := op(ctx)
+			finish(&BulkResult{Index: i, Value: value, Response: resp, Err: err})
+		}(i, op)
+	}
+	wg.Wait()
+
+	var failed []*BulkResult
+	for _, r := range results {
+		if r.Err != nil && r.Err != ErrBulkSkipped {
+			failed = append(failed, r)
+		}
+	}
+	if len(failed) > 0 {
+		return results, &BulkError{Total: len(ops), Failed: failed}
+	}
+
+	return results, nil
+}
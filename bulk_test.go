@@ -0,0 +1,131 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClient_Bulk(t *testing.T) {
+	Convey("test Client Bulk", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var running, maxRunning int32
+		mux.HandleFunc("/api/v9/projects/", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+			for {
+				max := atomic.LoadInt32(&maxRunning)
+				if n <= max || atomic.CompareAndSwapInt32(&maxRunning, max, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			id := strings.TrimPrefix(r.URL.Path, "/api/v9/projects/")
+			if id == "p3" || id == "p7" {
+				w.WriteHeader(http.StatusNotFound)
+				fmt.Fprint(w, `{"error": "Not Found"}`)
+				return
+			}
+			fmt.Fprintf(w, `{"project": {"id": %q}}`, id)
+		})
+
+		var ops []BulkFunc
+		for i := 0; i < 10; i++ {
+			id := fmt.Sprintf("p%d", i)
+			ops = append(ops, func(ctx context.Context) (interface{}, *Response, error) {
+				return client.Projects.GetProjectCtx(ctx, id)
+			})
+		}
+
+		var progress []BulkProgress
+		results, err := client.Bulk(context.Background(), ops, &BulkOptions{
+			Concurrency: 3,
+			Progress:    func(p BulkProgress) { progress = append(progress, p) },
+		})
+
+		var bulkErr *BulkError
+		So(errors.As(err, &bulkErr), ShouldBeTrue)
+		So(bulkErr.Total, ShouldEqual, 10)
+		So(bulkErr.Failed, ShouldHaveLength, 2)
+		So(bulkErr.Failed[0].Index, ShouldEqual, 3)
+		So(bulkErr.Failed[1].Index, ShouldEqual, 7)
+
+		So(results, ShouldHaveLength, 10)
+		for i, r := range results {
+			So(r.Index, ShouldEqual, i)
+			if i == 3 || i == 7 {
+				So(r.Err, ShouldNotBeNil)
+				So(r.Response.StatusCode, ShouldEqual, http.StatusNotFound)
+				continue
+			}
+			So(r.Err, ShouldBeNil)
+			So(r.Value.(*Project).ID, ShouldEqual, fmt.Sprintf("p%d", i))
+		}
+
+		So(atomic.LoadInt32(&maxRunning), ShouldBeLessThanOrEqualTo, 3)
+		So(progress, ShouldHaveLength, 10)
+		So(progress[9].Done, ShouldEqual, 10)
+		So(progress[9].Failed, ShouldEqual, 2)
+		So(progress[9].Total, ShouldEqual, 10)
+	})
+}
+
+func TestClient_BulkStopOnError(t *testing.T) {
+	Convey("test Client Bulk stopping on the first error", t, func() {
+		_, server, client := setup(t)
+		defer teardown(server)
+
+		var mu sync.Mutex
+		var started []int
+		var ops []BulkFunc
+		for i := 0; i < 5; i++ {
+			i := i
+			ops = append(ops, func(ctx context.Context) (interface{}, *Response, error) {
+				mu.Lock()
+				started = append(started, i)
+				mu.Unlock()
+				if i == 1 {
+					return nil, nil, errors.New("boom")
+				}
+				return i, nil, nil
+			})
+		}
+
+		var last BulkProgress
+		results, err := client.Bulk(context.Background(), ops, &BulkOptions{
+			Concurrency: 1,
+			StopOnError: true,
+			Progress:    func(p BulkProgress) { last = p },
+		})
+		So(err, ShouldNotBeNil)
+		So(errors.Cause(err.(*BulkError).Failed[0].Err).Error(), ShouldEqual, "boom")
+		So(started, ShouldResemble, []int{0, 1})
+		So(results[0].Value, ShouldEqual, 0)
+		So(results[2].Err, ShouldEqual, ErrBulkSkipped)
+		So(results[4].Err, ShouldEqual, ErrBulkSkipped)
+		So(last, ShouldResemble, BulkProgress{Total: 5, Done: 5, Failed: 1, Skipped: 3, Result: results[4]})
+	})
+}
+
+func TestClient_BulkEmpty(t *testing.T) {
+	Convey("test Client Bulk without operations", t, func() {
+		_, server, client := setup(t)
+		defer teardown(server)
+
+		results, err := client.Bulk(context.Background(), nil, nil)
+		So(err, ShouldBeNil)
+		So(results, ShouldBeEmpty)
+	})
+}
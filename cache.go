@@ -0,0 +1,149 @@
+package swarm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ErrNotModified is returned when Swarm responds with 304 Not Modified, but
+// there is no cached response to serve, e.g. because it was evicted.
+var ErrNotModified = errors.New("not modified, but no cached response available")
+
+// CacheStore describes the interface that all (custom) response caches must
+// implement. Implementations must be safe for concurrent use.
+type CacheStore interface {
+	Get(key string) (*CacheEntry, bool)
+	Set(key string, entry *CacheEntry)
+}
+
+// CacheEntry represents a cached response body together with the validators
+// used to revalidate it.
+type CacheEntry struct {
+	ETag         string
+	LastModified string
+	Body         []byte
+	StoredAt     time.Time
+}
+
+// MemoryCache is an in-memory CacheStore. Entries are evicted once they are
+// older than the configured TTL.
+type MemoryCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*CacheEntry
+}
+
+// NewMemoryCache returns a new MemoryCache. A ttl of zero or less keeps
+// entries forever.
+func NewMemoryCache(ttl time.Duration) *MemoryCache {
+	return &MemoryCache{
+		ttl:     ttl,
+		entries: make(map[string]*CacheEntry),
+	}
+}
+
+// Get returns the entry stored under key, if it has not yet expired.
+func (m *MemoryCache) Get(key string) (*CacheEntry, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if m.expired(entry) {
+		delete(m.entries, key)
+		return nil, false
+	}
+
+	return entry, true
+}
+
+// Set stores entry under key and evicts all expired entries.
+func (m *MemoryCache) Set(key string, entry *CacheEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for k, e := range m.entries {
+		if m.expired(e) {
+			delete(m.entries, k)
+		}
+	}
+	m.entries[key] = entry
+}
+
+func (m *MemoryCache) expired(entry *CacheEntry) bool {
+	return m.ttl > 0 && time.Since(entry.StoredAt) > m.ttl
+}
+
+// cacheKey returns the key used to cache the response of req. The key is
+// made up of the URL and a hash of the credentials used for the request, so
+// responses are never shared between different users.
+func cacheKey(req *http.Request) string {
+	h := sha256.New()
+	for _, header := range []string{"Authorization", "JOB-TOKEN", "PRIVATE-TOKEN", "SUDO"} {
+		io.WriteString(h, header+":"+req.Header.Get(header)+"\n")
+	}
+	return req.URL.String() + "#" + hex.EncodeToString(h.Sum(nil))
+}
+
+// prepareCachedRequest adds the conditional headers of a cached response to
+// req and returns the cached entry, or nil when nothing was cached.
+func (c *Client) prepareCachedRequest(key string, req *http.Request) *CacheEntry {
+	entry, ok := c.cache.Get(key)
+	if !ok {
+		return nil
+	}
+
+	if entry.ETag != "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+
+	return entry
+}
+
+// cacheResponse serves the cached body when Swarm responds with 304 and
+// stores new responses that carry a validator. It reports whether the body
+// of resp was replaced by the cached body.
+func (c *Client) cacheResponse(key string, entry *CacheEntry, resp *http.Response) (bool, error) {
+	switch {
+	case resp.StatusCode == http.StatusNotModified && entry != nil:
+		resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(entry.Body))
+		return true, nil
+
+	case resp.StatusCode == http.StatusOK:
+		etag := resp.Header.Get("ETag")
+		lastModified := resp.Header.Get("Last-Modified")
+		if etag == "" && lastModified == "" {
+			return false, nil
+		}
+
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return false, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+
+		c.cache.Set(key, &CacheEntry{
+			ETag:         etag,
+			LastModified: lastModified,
+			Body:         body,
+			StoredAt:     time.Now(),
+		})
+	}
+
+	return false, nil
+}
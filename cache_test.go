@@ -0,0 +1,91 @@
+package swarm
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClient_Cache(t *testing.T) {
+	Convey("test Client_Cache", t, func() {
+		mux, server, client := setup(t, WithCache(NewMemoryCache(time.Minute)))
+		defer teardown(server)
+
+		var hits, notModified int
+		mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			hits++
+			if r.Header.Get("If-None-Match") == `"v1"` {
+				notModified++
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+			w.Header().Set("ETag", `"v1"`)
+			fmt.Fprint(w, `{"projects": [{"id": "got-dev", "name": "Got-dev"}]}`)
+		})
+
+		want := []*Project{{ID: "got-dev", Name: "Got-dev"}}
+
+		projects, resp, err := client.Projects.ListProjects(nil)
+		So(err, ShouldBeNil)
+		So(resp.FromCache, ShouldBeFalse)
+		So(projects, ShouldResemble, want)
+
+		projects, resp, err = client.Projects.ListProjects(nil)
+		So(err, ShouldBeNil)
+		So(resp.FromCache, ShouldBeTrue)
+		So(resp.StatusCode, ShouldEqual, http.StatusNotModified)
+		So(projects, ShouldResemble, want)
+
+		projects, resp, err = client.Projects.ListProjects(nil, WithoutCache())
+		So(err, ShouldBeNil)
+		So(resp.FromCache, ShouldBeFalse)
+		So(projects, ShouldResemble, want)
+
+		// Requests made on behalf of another user never share cached responses.
+		projects, resp, err = client.Projects.ListProjects(nil, WithSudo("swarm"))
+		So(err, ShouldBeNil)
+		So(resp.FromCache, ShouldBeFalse)
+		So(projects, ShouldResemble, want)
+
+		So(hits, ShouldEqual, 4)
+		So(notModified, ShouldEqual, 1)
+	})
+}
+
+func TestClient_CacheNotModifiedWithoutEntry(t *testing.T) {
+	Convey("test Client_Cache with a 304 response which is not cached", t, func() {
+		mux, server, client := setup(t, WithCache(NewMemoryCache(time.Minute)))
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotModified)
+		})
+
+		_, resp, err := client.Projects.ListProjects(nil)
+		So(err, ShouldEqual, ErrNotModified)
+		So(resp.StatusCode, ShouldEqual, http.StatusNotModified)
+		So(resp.FromCache, ShouldBeFalse)
+	})
+}
+
+func TestMemoryCache(t *testing.T) {
+	Convey("test MemoryCache", t, func() {
+		cache := NewMemoryCache(10 * time.Millisecond)
+		cache.Set("fresh", &CacheEntry{ETag: `"v1"`, StoredAt: time.Now()})
+		cache.Set("stale", &CacheEntry{ETag: `"v1"`, StoredAt: time.Now().Add(-time.Second)})
+
+		entry, ok := cache.Get("fresh")
+		So(ok, ShouldBeTrue)
+		So(entry.ETag, ShouldEqual, `"v1"`)
+
+		_, ok = cache.Get("stale")
+		So(ok, ShouldBeFalse)
+
+		_, ok = cache.Get("missing")
+		So(ok, ShouldBeFalse)
+	})
+}
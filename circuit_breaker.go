@@ -0,0 +1,229 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// CircuitState represents the state of a circuit breaker.
+type CircuitState int
+
+// List of available circuit breaker states.
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// CircuitBreakerSettings configures the circuit breaker used by the client.
+type CircuitBreakerSettings struct {
+	// FailureThreshold is the number of consecutive failed attempts after
+	// which the circuit opens. Defaults to 5.
+	FailureThreshold int
+
+	// OpenTimeout is the time the circuit stays open before probe requests
+	// are let through. Defaults to 30 seconds.
+	OpenTimeout time.Duration
+
+	// HalfOpenProbes is the number of concurrent probe requests allowed while
+	// the circuit is half-open, and the number of successful probes needed to
+	// close the circuit again. Defaults to 1.
+	HalfOpenProbes int
+}
+
+// CircuitOpenError is returned for requests that are not sent, because the
+// circuit breaker for the host is open.
+type CircuitOpenError struct {
+	Host  string
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker for %s is open until %s", e.Host, e.Until.Format(time.RFC3339))
+}
+
+// circuitHostKey is the context key used to store the host of a request
+// guarded by the circuit breaker.
+type circuitHostKey struct{}
+
+// circuit holds the state of a single host.
+type circuit struct {
+	state     CircuitState
+	failures  int
+	openedAt  time.Time
+	probes    int
+	successes int
+}
+
+// circuitBreaker keeps a circuit per host.
+type circuitBreaker struct {
+	settings CircuitBreakerSettings
+	now      func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+func newCircuitBreaker(settings CircuitBreakerSettings) *circuitBreaker {
+	if settings.FailureThreshold <= 0 {
+		settings.FailureThreshold = 5
+	}
+	if settings.OpenTimeout <= 0 {
+		settings.OpenTimeout = 30 * time.Second
+	}
+	if settings.HalfOpenProbes <= 0 {
+		settings.HalfOpenProbes = 1
+	}
+
+	return &circuitBreaker{
+		settings: settings,
+		now:      time.Now,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+// circuit returns the circuit for host. The caller must hold the lock.
+func (b *circuitBreaker) circuit(host string) *circuit {
+	c, ok := b.circuits[host]
+	if !ok {
+		c = new(circuit)
+		b.circuits[host] = c
+	}
+	return c
+}
+
+// allow reports whether a request to host may be sent. It returns true when
+// the request is a probe of a half-open circuit, in which case done must be
+// called once the request is finished.
+func (b *circuitBreaker) allow(host string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(host)
+	switch c.state {
+	case CircuitOpen:
+		until := c.openedAt.Add(b.settings.OpenTimeout)
+		if b.now().Before(until) {
+			return false, &CircuitOpenError{Host: host, Until: until}
+		}
+		c.state = CircuitHalfOpen
+		c.probes = 0
+		c.successes = 0
+		fallthrough
+	case CircuitHalfOpen:
+		if c.probes >= b.settings.HalfOpenProbes {
+			return false, &CircuitOpenError{Host: host, Until: b.now()}
+		}
+		c.probes++
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// done releases a probe acquired with allow.
+func (b *circuitBreaker) done(host string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if c := b.circuit(host); c.state == CircuitHalfOpen && c.probes > 0 {
+		c.probes--
+	}
+}
+
+// record records the outcome of a single attempt and returns the new state.
+func (b *circuitBreaker) record(host string, failed bool) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(host)
+	switch c.state {
+	case CircuitClosed:
+		if !failed {
+			c.failures = 0
+			break
+		}
+		c.failures++
+		if c.failures >= b.settings.FailureThreshold {
+			b.open(c)
+		}
+	case CircuitHalfOpen:
+		if failed {
+			b.open(c)
+			break
+		}
+		c.successes++
+		if c.successes >= b.settings.HalfOpenProbes {
+			c.state = CircuitClosed
+			c.failures = 0
+		}
+	}
+
+	return c.state
+}
+
+// open opens circuit c. The caller must hold the lock.
+func (b *circuitBreaker) open(c *circuit) {
+	c.state = CircuitOpen
+	c.openedAt = b.now()
+	c.failures = 0
+}
+
+// state returns the current state of the circuit for host.
+func (b *circuitBreaker) state(host string) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.circuit(host).state
+}
+
+// CircuitState returns the state of the circuit breaker for the Swarm server
+// of the client. It always returns CircuitClosed when no circuit breaker is
+// configured.
+func (c *Client) CircuitState() CircuitState {
+	if c.breaker == nil {
+		return CircuitClosed
+	}
+	return c.breaker.state(c.baseURL.Host)
+}
+
+// checkRetry provides the callback for Client.CheckRetry. It uses the custom
+// retry policy if one is configured and records the outcome of every attempt
+// in the circuit breaker. Retries stop as soon as the circuit opens.
+func (c *Client) checkRetry(ctx context.Context, resp *http.Response, err error) (bool, error) {
+	checkRetry := c.retryHTTPCheck
+	if c.customCheckRetry != nil {
+		checkRetry = c.customCheckRetry
+	}
+	retry, checkErr := checkRetry(ctx, resp, err)
+
+	host, ok := ctx.Value(circuitHostKey{}).(string)
+	if !ok || c.breaker == nil || ctx.Err() != nil {
+		return retry, checkErr
+	}
+
+	// An attempt failed when the retry policy wants to retry it, or when the
+	// server did not respond properly at all. Throttled attempts (429) are
+	// left to the rate limiter and the retry backoff.
+	failed := err != nil || resp.StatusCode != http.StatusTooManyRequests && (retry || resp.StatusCode >= 500)
+	if c.breaker.record(host, failed) == CircuitOpen {
+		return false, checkErr
+	}
+
+	return retry, checkErr
+}
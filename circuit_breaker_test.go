@@ -0,0 +1,118 @@
+package swarm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	Convey("test Client_CircuitBreaker", t, func() {
+		mux, server, client := setup(t,
+			WithCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 3, OpenTimeout: time.Minute}),
+			WithCustomBackoff(func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+				return time.Millisecond
+			}),
+		)
+		defer teardown(server)
+
+		now := time.Now()
+		client.breaker.now = func() time.Time { return now }
+
+		var hits int
+		available := false
+		mux.HandleFunc("/api/v9/projects/got-dev", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			hits++
+			if !available {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `{"project": {"id": "got-dev", "name": "Got-dev"}}`)
+		})
+
+		// Retries stop as soon as the circuit opens.
+		_, resp, err := client.Projects.GetProject("got-dev")
+		So(err, ShouldNotBeNil)
+		So(resp.StatusCode, ShouldEqual, http.StatusServiceUnavailable)
+		So(hits, ShouldEqual, 3)
+		So(client.CircuitState(), ShouldEqual, CircuitOpen)
+
+		// Requests fail fast while the circuit is open.
+		_, _, err = client.Projects.GetProject("got-dev")
+		var openErr *CircuitOpenError
+		So(errors.As(err, &openErr), ShouldBeTrue)
+		So(openErr.Until, ShouldEqual, now.Add(time.Minute))
+		So(hits, ShouldEqual, 3)
+
+		// A failing probe opens the circuit again.
+		now = now.Add(2 * time.Minute)
+		_, _, err = client.Projects.GetProject("got-dev")
+		So(err, ShouldNotBeNil)
+		So(hits, ShouldEqual, 4)
+		So(client.CircuitState(), ShouldEqual, CircuitOpen)
+
+		// A successful probe closes the circuit.
+		now = now.Add(2 * time.Minute)
+		available = true
+		project, _, err := client.Projects.GetProject("got-dev")
+		So(err, ShouldBeNil)
+		So(project.ID, ShouldEqual, "got-dev")
+		So(hits, ShouldEqual, 5)
+		So(client.CircuitState(), ShouldEqual, CircuitClosed)
+	})
+}
+
+func TestClient_CircuitBreakerRateLimited(t *testing.T) {
+	Convey("test Client_CircuitBreaker with rate limited responses", t, func() {
+		mux, server, client := setup(t,
+			WithCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 3, OpenTimeout: time.Minute}),
+			WithCustomBackoff(func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+				return time.Millisecond
+			}),
+		)
+		defer teardown(server)
+
+		var hits int
+		mux.HandleFunc("/api/v9/projects/got-dev", func(w http.ResponseWriter, r *http.Request) {
+			hits++
+			w.WriteHeader(http.StatusTooManyRequests)
+		})
+
+		for i := 0; i < 2; i++ {
+			_, resp, err := client.Projects.GetProject("got-dev")
+			So(err, ShouldNotBeNil)
+			So(resp.StatusCode, ShouldEqual, http.StatusTooManyRequests)
+		}
+		So(hits, ShouldEqual, 2*(client.client.RetryMax+1))
+		So(client.CircuitState(), ShouldEqual, CircuitClosed)
+	})
+}
+
+func TestCircuitBreaker_HalfOpenProbes(t *testing.T) {
+	Convey("test CircuitBreaker_HalfOpenProbes", t, func() {
+		now := time.Now()
+		b := newCircuitBreaker(CircuitBreakerSettings{FailureThreshold: 1, HalfOpenProbes: 2})
+		b.now = func() time.Time { return now }
+
+		So(b.record("swarm.url", true), ShouldEqual, CircuitOpen)
+		So(b.state("other.url"), ShouldEqual, CircuitClosed)
+
+		now = now.Add(31 * time.Second)
+		for i := 0; i < 2; i++ {
+			probe, err := b.allow("swarm.url")
+			So(err, ShouldBeNil)
+			So(probe, ShouldBeTrue)
+		}
+		_, err := b.allow("swarm.url")
+		So(err, ShouldHaveSameTypeAs, &CircuitOpenError{})
+
+		So(b.record("swarm.url", false), ShouldEqual, CircuitHalfOpen)
+		b.done("swarm.url")
+		So(b.record("swarm.url", false), ShouldEqual, CircuitClosed)
+	})
+}
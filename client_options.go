@@ -16,6 +16,44 @@ func WithBaseURL(urlStr string) ClientOptionFunc {
 	}
 }
 
+// WithAuditSink records every mutating request made by the client in sink,
+// e.g. a JSONLinesSink. Secrets in the request body are redacted.
+func WithAuditSink(sink AuditSink) ClientOptionFunc {
+	return func(c *Client) error {
+		c.auditSink = sink
+		return nil
+	}
+}
+
+// WithCache enables caching of GET responses in the given store. Cached
+// responses are revalidated with If-None-Match and If-Modified-Since headers
+// and are served from the store when Swarm responds with 304 Not Modified.
+func WithCache(store CacheStore) ClientOptionFunc {
+	return func(c *Client) error {
+		c.cache = store
+		return nil
+	}
+}
+
+// WithCapabilityProbe discovers the capabilities of the Swarm server when the
+// client is created. Creating the client fails when the probe fails.
+func WithCapabilityProbe() ClientOptionFunc {
+	return func(c *Client) error {
+		c.probeCapabilities = true
+		return nil
+	}
+}
+
+// WithCircuitBreaker enables a circuit breaker per Swarm host. Once the
+// configured number of consecutive attempts failed, all requests fail fast
+// with a *CircuitOpenError until the circuit is probed again.
+func WithCircuitBreaker(settings CircuitBreakerSettings) ClientOptionFunc {
+	return func(c *Client) error {
+		c.breaker = newCircuitBreaker(settings)
+		return nil
+	}
+}
+
 // WithCustomBackoff can be used to configure a custom backoff policy.
 func WithCustomBackoff(backoff retryablehttp.Backoff) ClientOptionFunc {
 	return func(c *Client) error {
@@ -54,7 +92,35 @@ func WithCustomLogger(logger retryablehttp.Logger) ClientOptionFunc {
 // WithCustomRetry can be used to configure a custom retry policy.
 func WithCustomRetry(checkRetry retryablehttp.CheckRetry) ClientOptionFunc {
 	return func(c *Client) error {
-		c.client.CheckRetry = checkRetry
+		c.customCheckRetry = checkRetry
+		return nil
+	}
+}
+
+// WithDefaultServer scopes all requests to the Perforce server with the given
+// ID, for Swarm instances connected to multiple Perforce servers. Single
+// requests can be scoped to another server using WithServer.
+func WithDefaultServer(id string) ClientOptionFunc {
+	return func(c *Client) error {
+		c.server = id
+		return nil
+	}
+}
+
+// WithDryRun enables dry-run mode. POST, PUT, PATCH and DELETE requests are
+// not sent, instead they are passed to hook (if not nil) and returned in the
+// DryRun field of the Response. GET requests are still sent.
+func WithDryRun(hook DryRunFunc) ClientOptionFunc {
+	return func(c *Client) error {
+		c.dryRunConfig = dryRunConfig{enabled: true, hook: hook}
+		return nil
+	}
+}
+
+// WithJSONDecoder replaces json.Unmarshal for decoding responses.
+func WithJSONDecoder(decoder JSONDecoder) ClientOptionFunc {
+	return func(c *Client) error {
+		c.decodeConfig.decoder = decoder
 		return nil
 	}
 }
@@ -67,10 +133,19 @@ func WithHTTPClient(httpClient *http.Client) ClientOptionFunc {
 	}
 }
 
+// WithRawCapture keeps the original JSON of every response in the Raw field
+// of the Response, alongside the decoded result.
+func WithRawCapture() ClientOptionFunc {
+	return func(c *Client) error {
+		c.decodeConfig.raw = true
+		return nil
+	}
+}
+
 // WithRequestLogHook can be used to configure a custom request log hook.
 func WithRequestLogHook(hook retryablehttp.RequestLogHook) ClientOptionFunc {
 	return func(c *Client) error {
-		c.client.RequestLogHook = hook
+		c.customRequestLogHook = hook
 		return nil
 	}
 }
@@ -78,7 +153,27 @@ func WithRequestLogHook(hook retryablehttp.RequestLogHook) ClientOptionFunc {
 // WithResponseLogHook can be used to configure a custom response log hook.
 func WithResponseLogHook(hook retryablehttp.ResponseLogHook) ClientOptionFunc {
 	return func(c *Client) error {
-		c.client.ResponseLogHook = hook
+		c.customResponseLogHook = hook
+		return nil
+	}
+}
+
+// WithStrictDecoding reports fields of responses which are not decoded into
+// the result, e.g. after a Swarm upgrade added fields. The fields are listed
+// in the UnknownFields field of the Response and are logged as a warning
+// using the configured logger.
+func WithStrictDecoding() ClientOptionFunc {
+	return func(c *Client) error {
+		c.decodeConfig.strict = true
+		return nil
+	}
+}
+
+// WithInstrumenter can be used to instrument all API calls, e.g. to collect
+// metrics or tracing spans per Swarm endpoint.
+func WithInstrumenter(instrumenter Instrumenter) ClientOptionFunc {
+	return func(c *Client) error {
+		c.instrumenter = instrumenter
 		return nil
 	}
 }
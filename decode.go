@@ -0,0 +1,178 @@
+package swarm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+// JSONDecoder decodes the JSON encoded data into v.
+type JSONDecoder func(data []byte, v interface{}) error
+
+// decodeConfig holds the decoding settings of a client.
+type decodeConfig struct {
+	// decoder replaces json.Unmarshal when set.
+	decoder JSONDecoder
+
+	// strict reports fields in responses which are not part of the result.
+	strict bool
+
+	// raw keeps the original JSON in the Response.
+	raw bool
+}
+
+// buffered reports whether the body has to be read before decoding it.
+func (cfg decodeConfig) buffered() bool {
+	return cfg.decoder != nil || cfg.strict || cfg.raw
+}
+
+// decode decodes the body of the response into v, applying the decoding
+// settings of the client.
+func (c *Client) decode(response *Response, body io.Reader, v interface{}) error {
+	cfg := c.decodeConfig
+	if !cfg.buffered() {
+		if v == nil {
+			return nil
+		}
+		return json.NewDecoder(body).Decode(v)
+	}
+
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	if cfg.raw {
+		response.Raw = json.RawMessage(data)
+	}
+	if v == nil {
+		return nil
+	}
+
+	decoder := cfg.decoder
+	if decoder == nil {
+		decoder = json.Unmarshal
+	}
+	if err := decoder(data, v); err != nil {
+		return err
+	}
+
+	if cfg.strict {
+		var doc interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return err
+		}
+		response.UnknownFields = unknownFields(doc, reflect.TypeOf(v), "")
+		sort.Strings(response.UnknownFields)
+		if len(response.UnknownFields) > 0 {
+			c.warn("Unknown fields in Swarm response", "url", response.Request.URL.String(), "fields", strings.Join(response.UnknownFields, ","))
+		}
+	}
+
+	return nil
+}
+
+// warn logs a warning using the logger of the retryablehttp client, if any.
+func (c *Client) warn(msg string, keysAndValues ...interface{}) {
+	switch logger := c.client.Logger.(type) {
+	case retryablehttp.LeveledLogger:
+		logger.Warn(msg, keysAndValues...)
+	case retryablehttp.Logger:
+		logger.Printf("[WARN] %s %v", msg, keysAndValues)
+	}
+}
+
+// unknownFields returns the paths of all object keys in doc which are not
+// decoded into a value of type t. Values decoded into interface{} and maps
+// of interface{} are never reported.
+func unknownFields(doc interface{}, t reflect.Type, path string) []string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	var unknown []string
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := structFields(t)
+			for key, value := range doc {
+				ft, ok := fields[strings.ToLower(key)]
+				if !ok {
+					unknown = append(unknown, joinPath(path, key))
+					continue
+				}
+				unknown = append(unknown, unknownFields(value, ft, joinPath(path, key))...)
+			}
+		case reflect.Map:
+			for key, value := range doc {
+				unknown = append(unknown, unknownFields(value, t.Elem(), joinPath(path, key))...)
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, value := range doc {
+				unknown = append(unknown, unknownFields(value, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+			}
+		}
+	}
+
+	return unknown
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// structFieldsCache caches the decoded fields per struct type.
+var structFieldsCache sync.Map
+
+// structFields returns the types of the fields of struct type t by their
+// lower case JSON name, including the fields of embedded structs.
+func structFields(t reflect.Type) map[string]reflect.Type {
+	if fields, ok := structFieldsCache.Load(t); ok {
+		return fields.(map[string]reflect.Type)
+	}
+
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			for n, t := range structFields(ft) {
+				if _, ok := fields[n]; !ok {
+					fields[n] = t
+				}
+			}
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		fields[strings.ToLower(name)] = f.Type
+	}
+
+	structFieldsCache.Store(t, fields)
+	return fields
+}
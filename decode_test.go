@@ -0,0 +1,127 @@
+package swarm
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const driftedProjects = `{
+  "projects": [
+	{
+	  "id": "got-dev",
+	  "name": "Got-dev",
+	  "private": false,
+	  "branches": [
+		{"id": "main", "paths": [], "minimumUpVotes": 1, "requiredVotes": 2,
+		 "defaults": {"reviewers": {"users": {"a": {"required": true}}}}}
+	  ]
+	}
+  ],
+  "lastSeen": null
+}`
+
+// warnLogger is a retryablehttp.LeveledLogger recording warnings.
+type warnLogger struct {
+	warnings []string
+}
+
+func (l *warnLogger) Error(msg string, keysAndValues ...interface{}) {}
+func (l *warnLogger) Info(msg string, keysAndValues ...interface{})  {}
+func (l *warnLogger) Debug(msg string, keysAndValues ...interface{}) {}
+func (l *warnLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.warnings = append(l.warnings, fmt.Sprint(msg, keysAndValues))
+}
+
+func TestStrictDecoding(t *testing.T) {
+	Convey("test strict decoding", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		logger := &warnLogger{}
+		So(WithCustomLeveledLogger(logger)(client), ShouldBeNil)
+		So(WithStrictDecoding()(client), ShouldBeNil)
+
+		mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, driftedProjects)
+		})
+
+		projects, resp, err := client.Projects.ListProjects(nil)
+		So(err, ShouldBeNil)
+		So(projects[0].Branches[0].MinimumUpVotes, ShouldResemble, Int(1))
+		So(resp.UnknownFields, ShouldResemble, []string{
+			"lastSeen",
+			"projects[0].branches[0].requiredVotes",
+			"projects[0].private",
+		})
+		So(resp.Raw, ShouldBeNil)
+		So(logger.warnings, ShouldHaveLength, 1)
+		So(logger.warnings[0], ShouldContainSubstring, "projects[0].private")
+	})
+}
+
+func TestRawCapture(t *testing.T) {
+	Convey("test raw capture with a custom decoder", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		decoded := 0
+		So(WithRawCapture()(client), ShouldBeNil)
+		So(WithJSONDecoder(func(data []byte, v interface{}) error {
+			decoded++
+			return json.Unmarshal(data, v)
+		})(client), ShouldBeNil)
+
+		mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, driftedProjects)
+		})
+
+		projects, resp, err := client.Projects.ListProjects(nil)
+		So(err, ShouldBeNil)
+		So(projects, ShouldHaveLength, 1)
+		So(decoded, ShouldEqual, 1)
+		So(resp.UnknownFields, ShouldBeNil)
+		So(string(resp.Raw), ShouldEqual, driftedProjects)
+
+		var raw map[string]interface{}
+		So(json.Unmarshal(resp.Raw, &raw), ShouldBeNil)
+		So(raw, ShouldContainKey, "lastSeen")
+	})
+}
+
+func TestUnknownFields(t *testing.T) {
+	Convey("test unknownFields", t, func() {
+		type inner struct {
+			Name string `json:"name"`
+		}
+		type embedded struct {
+			Embedded string `json:"embedded"`
+		}
+		type outer struct {
+			embedded
+			ID      int                    `json:"id"`
+			Items   []*inner               `json:"items"`
+			ByName  map[string]inner       `json:"byName"`
+			Any     interface{}            `json:"any"`
+			Loose   map[string]interface{} `json:"loose"`
+			Ignored string                 `json:"-"`
+		}
+
+		var doc interface{}
+		So(json.Unmarshal([]byte(`{
+			"ID": 1, "embedded": "e", "Ignored": "x",
+			"items": [{"name": "a", "extra": 1}],
+			"byName": {"k": {"name": "b", "other": 2}},
+			"any": {"free": 1}, "loose": {"free": {"form": 1}}
+		}`), &doc), ShouldBeNil)
+
+		unknown := unknownFields(doc, reflect.TypeOf(&outer{}), "")
+		sort.Strings(unknown)
+		So(unknown, ShouldResemble, []string{"Ignored", "byName.k.other", "items[0].extra"})
+	})
+}
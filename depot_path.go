@@ -0,0 +1,242 @@
+package swarm
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DepotPath is a single line of a branch's paths, e.g. "//depot/main/..." or
+// "-//depot/main/generated/...". A leading "-" excludes the path.
+type DepotPath struct {
+	path string
+	re   *regexp.Regexp
+}
+
+// ErrInvalidDepotPath is returned when a depot path has an invalid syntax.
+var ErrInvalidDepotPath = errors.New("invalid depot path")
+
+// ParseDepotPath parses and validates a depot path. Paths have to be in depot
+// syntax, may contain the wildcards "...", "*" and "%%1" and may be excluded
+// with a leading "-".
+func ParseDepotPath(s string) (DepotPath, error) {
+	s = strings.TrimSpace(s)
+	p := strings.TrimPrefix(s, "-")
+
+	switch {
+	case !strings.HasPrefix(p, "//"):
+		return DepotPath{}, errors.Wrapf(ErrInvalidDepotPath, "%q must start with //", s)
+	case strings.ContainsAny(p, "@#\n\r"):
+		return DepotPath{}, errors.Wrapf(ErrInvalidDepotPath, "%q contains a revision specifier or line break", s)
+	case strings.Contains(p[2:], "//"):
+		return DepotPath{}, errors.Wrapf(ErrInvalidDepotPath, "%q contains an empty directory", s)
+	case strings.Contains(strings.Replace(p, "...", "", -1), ".."):
+		return DepotPath{}, errors.Wrapf(ErrInvalidDepotPath, "%q contains a relative directory", s)
+	case strings.Contains(strings.Replace(p, "%%", "", -1), "%"):
+		return DepotPath{}, errors.Wrapf(ErrInvalidDepotPath, "%q contains an unsupported %% character", s)
+	}
+
+	depot := strings.SplitN(p[2:], "/", 2)[0]
+	if depot == "" || strings.Contains(depot, "*") || strings.Contains(depot, "...") {
+		return DepotPath{}, errors.Wrapf(ErrInvalidDepotPath, "%q has an invalid depot name", s)
+	}
+
+	return DepotPath{path: s, re: depotPathRegexp(p)}, nil
+}
+
+// Exclude reports whether the path excludes files.
+func (p DepotPath) Exclude() bool {
+	return strings.HasPrefix(p.path, "-")
+}
+
+// Path returns the path without the exclusion prefix.
+func (p DepotPath) Path() string {
+	return strings.TrimPrefix(p.path, "-")
+}
+
+// String returns the path as given, including the exclusion prefix.
+func (p DepotPath) String() string {
+	return p.path
+}
+
+// Match reports whether file is matched by the path, ignoring exclusion.
+func (p DepotPath) Match(file string) bool {
+	return p.re != nil && p.re.MatchString(file)
+}
+
+// depotPathRegexp compiles the regular expression matching the files of a
+// depot path without exclusion prefix.
+func depotPathRegexp(path string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^")
+	for len(path) > 0 {
+		switch {
+		case strings.HasPrefix(path, "..."):
+			b.WriteString(".*")
+			path = path[3:]
+		case strings.HasPrefix(path, "*"):
+			b.WriteString("[^/]*")
+			path = path[1:]
+		case len(path) > 2 && strings.HasPrefix(path, "%%") && path[2] >= '0' && path[2] <= '9':
+			b.WriteString("[^/]*")
+			path = path[3:]
+		default:
+			b.WriteString(regexp.QuoteMeta(path[:1]))
+			path = path[1:]
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String())
+}
+
+// DepotPaths is an ordered list of depot paths, as used by branches.
+type DepotPaths []DepotPath
+
+// ParseDepotPaths parses and validates a list of depot paths. Empty lines are
+// ignored.
+func ParseDepotPaths(paths []string) (DepotPaths, error) {
+	result := make(DepotPaths, 0, len(paths))
+	for _, path := range paths {
+		if strings.TrimSpace(path) == "" {
+			continue
+		}
+		p, err := ParseDepotPath(path)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// Match reports whether file is matched by the paths. Later paths override
+// earlier ones, so an exclusion only removes files matched before it.
+func (ps DepotPaths) Match(file string) bool {
+	matched := false
+	for _, p := range ps {
+		if p.Match(file) {
+			matched = !p.Exclude()
+		}
+	}
+	return matched
+}
+
+// String returns the paths in the form expected by Swarm, one path per line.
+func (ps DepotPaths) String() string {
+	lines := make([]string, 0, len(ps))
+	for _, p := range ps {
+		lines = append(lines, p.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Strings returns the paths as given, including the exclusion prefixes.
+func (ps DepotPaths) Strings() []string {
+	lines := make([]string, 0, len(ps))
+	for _, p := range ps {
+		lines = append(lines, p.String())
+	}
+	return lines
+}
+
+// SetPaths validates the given depot paths and sets them as the paths of the
+// branch. Empty paths are dropped.
+func (o *BranchOptions) SetPaths(paths ...string) error {
+	ps, err := ParseDepotPaths(paths)
+	if err != nil {
+		return err
+	}
+	o.Paths = stringPointers(ps.Strings())
+	return nil
+}
+
+// validate checks the syntax of the paths of the branch, if set.
+func (o *BranchOptions) validate() error {
+	if o == nil || o.Paths == nil {
+		return nil
+	}
+	_, err := ParseDepotPaths(stringValues(o.Paths))
+	return err
+}
+
+// validateBranches checks the syntax of the paths of all branches.
+func validateBranches(branches []*BranchOptions) error {
+	for _, b := range branches {
+		if err := b.validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Contains reports whether file belongs to the branch. Paths which can't be
+// parsed are ignored.
+func (b Branch) Contains(file string) bool {
+	return branchPaths(b).Match(file)
+}
+
+// branchPaths parses the paths of a branch, ignoring invalid paths.
+func branchPaths(b Branch) DepotPaths {
+	ps := make(DepotPaths, 0, len(b.Paths))
+	for _, path := range b.Paths {
+		if p, err := ParseDepotPath(path); err == nil {
+			ps = append(ps, p)
+		}
+	}
+	return ps
+}
+
+// BranchMatch is a branch of a project which contains a file.
+type BranchMatch struct {
+	Project *Project
+	Branch  *Branch
+}
+
+func (m BranchMatch) String() string {
+	return fmt.Sprintf("%s/%s", m.Project.ID, m.Branch.ID)
+}
+
+// BranchMatcher matches depot files to the branches of projects. The paths
+// of all branches are parsed once, so it can match many files efficiently.
+type BranchMatcher struct {
+	branches []branchPathsMatch
+}
+
+type branchPathsMatch struct {
+	match BranchMatch
+	paths DepotPaths
+}
+
+// NewBranchMatcher returns a BranchMatcher for the branches of projects.
+func NewBranchMatcher(projects []*Project) *BranchMatcher {
+	m := &BranchMatcher{}
+	for _, p := range projects {
+		for i := range p.Branches {
+			m.branches = append(m.branches, branchPathsMatch{
+				match: BranchMatch{Project: p, Branch: &p.Branches[i]},
+				paths: branchPaths(p.Branches[i]),
+			})
+		}
+	}
+	return m
+}
+
+// Match returns all branches which contain the depot file.
+func (m *BranchMatcher) Match(file string) []BranchMatch {
+	var matches []BranchMatch
+	for _, b := range m.branches {
+		if b.paths.Match(file) {
+			matches = append(matches, b.match)
+		}
+	}
+	return matches
+}
+
+// MatchBranches returns all branches of the given projects which contain the
+// depot file. The paths are matched locally, without contacting Swarm. Use a
+// BranchMatcher to match many files.
+func MatchBranches(projects []*Project, file string) []BranchMatch {
+	return NewBranchMatcher(projects).Match(file)
+}
@@ -0,0 +1,130 @@
+package swarm
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestParseDepotPath(t *testing.T) {
+	Convey("test ParseDepotPath", t, func() {
+		valid := []string{
+			"//depot/main/...",
+			"-//depot/main/generated/...",
+			"//xxx.Mainline/abvc_ArtDev/Assets/*.cs",
+			"//depot/%%1/src/...",
+			" //depot/main/file.txt ",
+		}
+		for _, path := range valid {
+			_, err := ParseDepotPath(path)
+			So(err, ShouldBeNil)
+		}
+
+		invalid := []string{
+			"",
+			"depot/main/...",
+			"/depot/main/...",
+			"//depot/main/...@12",
+			"//depot/main/a.c#3",
+			"//depot//main/...",
+			"//depot/../main/...",
+			"//depot/100%/...",
+			"//.../main",
+			"//*/main",
+		}
+		for _, path := range invalid {
+			_, err := ParseDepotPath(path)
+			So(errors.Is(err, ErrInvalidDepotPath), ShouldBeTrue)
+		}
+
+		p, err := ParseDepotPath("-//depot/main/generated/...")
+		So(err, ShouldBeNil)
+		So(p.Exclude(), ShouldBeTrue)
+		So(p.Path(), ShouldEqual, "//depot/main/generated/...")
+		So(p.String(), ShouldEqual, "-//depot/main/generated/...")
+		So(p.Match("//depot/main/generated/a.go"), ShouldBeTrue)
+		So(DepotPath{}.Match("//depot/main/generated/a.go"), ShouldBeFalse)
+	})
+}
+
+func TestDepotPaths_Match(t *testing.T) {
+	Convey("test DepotPaths Match", t, func() {
+		ps, err := ParseDepotPaths([]string{
+			"//depot/main/...",
+			"-//depot/main/generated/...",
+			"//depot/main/generated/keep.go",
+			"//depot/tools/*.sh",
+			"//depot/%%1/docs/...",
+			"",
+		})
+		So(err, ShouldBeNil)
+		So(ps, ShouldHaveLength, 5)
+
+		So(ps.Match("//depot/main/src/a.go"), ShouldBeTrue)
+		So(ps.Match("//depot/main/generated/b.go"), ShouldBeFalse)
+		So(ps.Match("//depot/main/generated/keep.go"), ShouldBeTrue)
+		So(ps.Match("//depot/tools/build.sh"), ShouldBeTrue)
+		So(ps.Match("//depot/tools/ci/build.sh"), ShouldBeFalse)
+		So(ps.Match("//depot/rel/docs/index.md"), ShouldBeTrue)
+		So(ps.Match("//depot/mainline/a.go"), ShouldBeFalse)
+		So(ps.Match("//other/main/a.go"), ShouldBeFalse)
+
+		So(ps.String(), ShouldEqual, "//depot/main/...\n-//depot/main/generated/...\n//depot/main/generated/keep.go\n//depot/tools/*.sh\n//depot/%%1/docs/...")
+	})
+}
+
+func TestBranchOptions_SetPaths(t *testing.T) {
+	Convey("test BranchOptions SetPaths", t, func() {
+		opt := &BranchOptions{}
+		So(opt.SetPaths("//depot/main/...", "-//depot/main/tmp/..."), ShouldBeNil)
+		So(stringValues(opt.Paths), ShouldResemble, []string{"//depot/main/...", "-//depot/main/tmp/..."})
+
+		So(opt.SetPaths("depot/main/..."), ShouldNotBeNil)
+		So(stringValues(opt.Paths), ShouldResemble, []string{"//depot/main/...", "-//depot/main/tmp/..."})
+	})
+}
+
+func TestProjectsService_CreateProjectInvalidPaths(t *testing.T) {
+	Convey("test ProjectsService_CreateProject with invalid paths", t, func() {
+		_, server, client := setup(t)
+		defer teardown(server)
+
+		_, _, err := client.Projects.CreateProject(&CreateProjectOptions{
+			Name:     String("Got-dev"),
+			Branches: []*BranchOptions{{Name: String("Client"), Paths: []*string{String("//depot/main/..."), String("//depot/main/a.c#2")}}},
+		})
+		So(errors.Is(err, ErrInvalidDepotPath), ShouldBeTrue)
+	})
+}
+
+func TestMatchBranches(t *testing.T) {
+	Convey("test MatchBranches", t, func() {
+		projects := []*Project{
+			{ID: "game", Branches: []Branch{
+				{ID: "client", Paths: []string{"//depot/game/client/...", "//depot/game/common/..."}},
+				{ID: "server", Paths: []string{"//depot/game/server/...", "//depot/game/common/..."}},
+			}},
+			{ID: "tools", Branches: []Branch{
+				{ID: "main", Paths: []string{"//depot/...", "-//depot/game/...", "not a path"}},
+			}},
+		}
+
+		matches := MatchBranches(projects, "//depot/game/common/util.go")
+		So(matches, ShouldHaveLength, 2)
+		So(matches[0].String(), ShouldEqual, "game/client")
+		So(matches[1].String(), ShouldEqual, "game/server")
+
+		matches = MatchBranches(projects, "//depot/tools/build.sh")
+		So(matches, ShouldHaveLength, 1)
+		So(matches[0].Project.ID, ShouldEqual, "tools")
+		So(matches[0].Branch.ID, ShouldEqual, "main")
+
+		So(MatchBranches(projects, "//other/file"), ShouldBeEmpty)
+
+		m := NewBranchMatcher(projects)
+		So(m.Match("//depot/game/server/main.go"), ShouldHaveLength, 1)
+		So(m.Match("//depot/game/server/main.go")[0].String(), ShouldEqual, "game/server")
+		So(m.Match("//depot/docs/readme.md")[0].String(), ShouldEqual, "tools/main")
+	})
+}
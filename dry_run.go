@@ -0,0 +1,99 @@
+package swarm
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+// DryRunRequest is a fully encoded request which was not sent to Swarm,
+// because dry-run mode is enabled.
+type DryRunRequest struct {
+	Method string
+	URL    string
+	Header http.Header
+
+	// Body is the encoded body of the request.
+	Body []byte
+
+	// Form holds the decoded body of form encoded requests.
+	Form url.Values
+}
+
+func (r *DryRunRequest) String() string {
+	if len(r.Body) == 0 {
+		return fmt.Sprintf("%s %s", r.Method, r.URL)
+	}
+	return fmt.Sprintf("%s %s\n%s", r.Method, r.URL, r.Body)
+}
+
+// DryRunFunc is called with every request which is not sent in dry-run mode.
+type DryRunFunc func(*DryRunRequest)
+
+// dryRunConfig holds the dry-run settings of a client or request.
+type dryRunConfig struct {
+	enabled bool
+	hook    DryRunFunc
+}
+
+// dryRun returns the dry-run settings for req. The settings of the request
+// take precedence over the ones of the client.
+func (c *Client) dryRun(req *retryablehttp.Request) dryRunConfig {
+	cfg := c.dryRunConfig
+	if override := requestConfigFrom(req.Context()).dryRun; override != nil {
+		cfg.enabled = override.enabled
+		if override.hook != nil {
+			cfg.hook = override.hook
+		}
+	}
+	return cfg
+}
+
+// mutating reports whether the request changes data in Swarm.
+func mutating(req *retryablehttp.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// dryRunResponse returns the response for a request which is not sent.
+func dryRunResponse(req *retryablehttp.Request, hook DryRunFunc) (*Response, error) {
+	body, err := req.BodyBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &DryRunRequest{
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Header: req.Header.Clone(),
+		Body:   body,
+	}
+	if mediaType, _, _ := mime.ParseMediaType(req.Header.Get("Content-Type")); mediaType == "application/x-www-form-urlencoded" {
+		if r.Form, err = url.ParseQuery(string(body)); err != nil {
+			return nil, err
+		}
+	}
+
+	if hook != nil {
+		hook(r)
+	}
+
+	return &Response{
+		Response: &http.Response{
+			Status:     http.StatusText(http.StatusNoContent),
+			StatusCode: http.StatusNoContent,
+			Header:     make(http.Header),
+			Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+			Request:    req.Request,
+		},
+		DryRun: r,
+	}, nil
+}
@@ -0,0 +1,93 @@
+package swarm
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestDryRun_CreateProject(t *testing.T) {
+	Convey("test dry-run of CreateProject", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var previews []*DryRunRequest
+		So(WithDryRun(func(r *DryRunRequest) { previews = append(previews, r) })(client), ShouldBeNil)
+
+		mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("Request sent in dry-run mode: %s %s", r.Method, r.URL)
+		})
+
+		project, resp, err := client.Projects.CreateProject(&CreateProjectOptions{
+			Name:    String("got-dev"),
+			Members: []*string{String("eyotang"), String("tangyongqiang")},
+		})
+		So(err, ShouldBeNil)
+		So(project, ShouldBeNil)
+		So(resp.StatusCode, ShouldEqual, http.StatusNoContent)
+		So(resp.DryRun, ShouldNotBeNil)
+		So(previews, ShouldResemble, []*DryRunRequest{resp.DryRun})
+
+		So(resp.DryRun.Method, ShouldEqual, http.MethodPost)
+		So(resp.DryRun.URL, ShouldEqual, server.URL+"/api/v9/projects")
+		So(resp.DryRun.Header.Get("Authorization"), ShouldBeEmpty)
+		So(resp.DryRun.Form, ShouldResemble, url.Values{
+			"name":      {"got-dev"},
+			"members[]": {"eyotang", "tangyongqiang"},
+		})
+		So(resp.DryRun.String(), ShouldStartWith, "POST "+server.URL+"/api/v9/projects\n")
+	})
+}
+
+func TestDryRun_UpdateProject(t *testing.T) {
+	Convey("test dry-run of UpdateProject with GETs going through", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		So(WithDryRun(nil)(client), ShouldBeNil)
+
+		var patched url.Values
+		mux.HandleFunc("/api/v9/projects/got-dev", handleMembersProject(t, &patched))
+
+		_, resp, err := client.Projects.RemoveBranch("got-dev", "server")
+		So(err, ShouldBeNil)
+		So(patched, ShouldBeNil)
+		So(resp.DryRun.Method, ShouldEqual, http.MethodPatch)
+		So(resp.DryRun.Form.Get("branches[0][id]"), ShouldEqual, "client")
+		So(resp.DryRun.Form.Get("branches[1][id]"), ShouldBeEmpty)
+
+		_, resp, err = client.Projects.RemoveBranch("got-dev", "server", WithoutDryRun())
+		So(err, ShouldBeNil)
+		So(resp.DryRun, ShouldBeNil)
+		So(patched.Get("branches[0][id]"), ShouldEqual, "client")
+	})
+}
+
+func TestDryRun_SetGlobalExclusions(t *testing.T) {
+	Convey("test per-request dry-run of SetGlobalExclusions", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/workflows/0", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprint(w, `{"workflow": {"id": 0, "name": "Global Workflow", "description": "global"}}`)
+		})
+		mux.HandleFunc("/api/v10/workflows/0", func(w http.ResponseWriter, r *http.Request) {
+			t.Errorf("Request sent in dry-run mode: %s %s", r.Method, r.URL)
+		})
+
+		var preview *DryRunRequest
+		err := client.Workflows.SetGlobalExclusions([]string{"qa"}, []string{"bot"},
+			WithRequestDryRun(func(r *DryRunRequest) { preview = r }))
+		So(err, ShouldBeNil)
+		So(preview, ShouldNotBeNil)
+		So(preview.Method, ShouldEqual, http.MethodPut)
+		So(preview.URL, ShouldEqual, server.URL+"/api/v10/workflows/0")
+		So(preview.Form.Get("description"), ShouldEqual, "global")
+		So(preview.Form["group_exclusions[rule][]"], ShouldResemble, []string{"swarm-group-qa"})
+		So(preview.Form["user_exclusions[rule][]"], ShouldResemble, []string{"bot"})
+	})
+}
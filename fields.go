@@ -0,0 +1,167 @@
+package swarm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+// Field is the name of a field of a Swarm resource. Fields are used with
+// WithFields to limit the fields returned by list and get calls.
+type Field interface {
+	// Name returns the name of the field as used by Swarm.
+	Name() string
+
+	// resource returns the resource the field belongs to.
+	resource() reflect.Type
+}
+
+// ProjectField is a field of a Project.
+type ProjectField string
+
+// Fields of a Project.
+const (
+	ProjectFieldID          ProjectField = "id"
+	ProjectFieldName        ProjectField = "name"
+	ProjectFieldDescription ProjectField = "description"
+	ProjectFieldMembers     ProjectField = "members"
+	ProjectFieldOwners      ProjectField = "owners"
+	ProjectFieldBranches    ProjectField = "branches"
+	ProjectFieldWorkflow    ProjectField = "workflow"
+	ProjectFieldDeleted     ProjectField = "deleted"
+)
+
+// Name implements Field.
+func (f ProjectField) Name() string { return string(f) }
+
+func (f ProjectField) resource() reflect.Type { return reflect.TypeOf(Project{}) }
+
+// WorkflowField is a field of a Workflow.
+type WorkflowField string
+
+// Fields of a Workflow.
+const (
+	WorkflowFieldID              WorkflowField = "id"
+	WorkflowFieldName            WorkflowField = "name"
+	WorkflowFieldDescription     WorkflowField = "description"
+	WorkflowFieldShared          WorkflowField = "shared"
+	WorkflowFieldOwners          WorkflowField = "owners"
+	WorkflowFieldOnSubmit        WorkflowField = "on_submit"
+	WorkflowFieldEndRules        WorkflowField = "end_rules"
+	WorkflowFieldAutoApprove     WorkflowField = "auto_approve"
+	WorkflowFieldCountedVotes    WorkflowField = "counted_votes"
+	WorkflowFieldGroupExclusions WorkflowField = "group_exclusions"
+	WorkflowFieldUserExclusions  WorkflowField = "user_exclusions"
+)
+
+// Name implements Field.
+func (f WorkflowField) Name() string { return string(f) }
+
+func (f WorkflowField) resource() reflect.Type { return reflect.TypeOf(Workflow{}) }
+
+// ReviewField is a field of a Review.
+type ReviewField string
+
+// Fields of a Review.
+const (
+	ReviewFieldID           ReviewField = "id"
+	ReviewFieldAuthor       ReviewField = "author"
+	ReviewFieldType         ReviewField = "type"
+	ReviewFieldDescription  ReviewField = "description"
+	ReviewFieldState        ReviewField = "state"
+	ReviewFieldStateLabel   ReviewField = "stateLabel"
+	ReviewFieldPending      ReviewField = "pending"
+	ReviewFieldCommitStatus ReviewField = "commitStatus"
+	ReviewFieldTestStatus   ReviewField = "testStatus"
+	ReviewFieldDeployStatus ReviewField = "deployStatus"
+	ReviewFieldChanges      ReviewField = "changes"
+	ReviewFieldCommits      ReviewField = "commits"
+	ReviewFieldCreated      ReviewField = "created"
+	ReviewFieldUpdated      ReviewField = "updated"
+	ReviewFieldVersions     ReviewField = "versions"
+)
+
+// Name implements Field.
+func (f ReviewField) Name() string { return string(f) }
+
+func (f ReviewField) resource() reflect.Type { return reflect.TypeOf(Review{}) }
+
+// InvalidFieldError is returned when a field does not exist on its resource.
+type InvalidFieldError struct {
+	Resource string
+	Field    string
+}
+
+func (e *InvalidFieldError) Error() string {
+	return fmt.Sprintf("unknown field %q for %s", e.Field, e.Resource)
+}
+
+// jsonFieldsCache caches the JSON field names per resource type.
+var jsonFieldsCache sync.Map
+
+// jsonFields returns the names of the JSON fields of struct type t.
+func jsonFields(t reflect.Type) map[string]bool {
+	if fields, ok := jsonFieldsCache.Load(t); ok {
+		return fields.(map[string]bool)
+	}
+
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = f.Name
+		}
+		fields[name] = true
+	}
+
+	jsonFieldsCache.Store(t, fields)
+	return fields
+}
+
+// validateField checks that f is a field of its resource.
+func validateField(f Field) error {
+	t := f.resource()
+	if !jsonFields(t)[f.Name()] {
+		return &InvalidFieldError{Resource: t.Name(), Field: f.Name()}
+	}
+	return nil
+}
+
+// FieldList validates the given fields and returns them in the form used by
+// the fields parameter, e.g. "id,name,branches".
+func FieldList(fields ...Field) (string, error) {
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if err := validateField(f); err != nil {
+			return "", err
+		}
+		names = append(names, f.Name())
+	}
+	return strings.Join(names, ","), nil
+}
+
+// WithFields limits the fields returned by a list or get call to the given
+// fields. It replaces the fields set in the options of the call, if any. Get
+// calls have no options, so WithFields is the way to select their fields.
+func WithFields(fields ...Field) RequestOptionFunc {
+	return func(req *retryablehttp.Request) error {
+		list, err := FieldList(fields...)
+		if err != nil {
+			return err
+		}
+		q := req.URL.Query()
+		q.Set("fields", list)
+		req.URL.RawQuery = q.Encode()
+		return nil
+	}
+}
@@ -0,0 +1,132 @@
+package swarm
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestFieldList(t *testing.T) {
+	Convey("test FieldList", t, func() {
+		list, err := FieldList(ProjectFieldID, ProjectFieldBranches)
+		So(err, ShouldBeNil)
+		So(list, ShouldEqual, "id,branches")
+
+		list, err = FieldList(WorkflowFieldGroupExclusions, ReviewFieldStateLabel)
+		So(err, ShouldBeNil)
+		So(list, ShouldEqual, "group_exclusions,stateLabel")
+
+		_, err = FieldList(
+			ProjectFieldID, ProjectFieldName, ProjectFieldDescription, ProjectFieldMembers,
+			ProjectFieldOwners, ProjectFieldBranches,
+			WorkflowFieldID, WorkflowFieldName, WorkflowFieldDescription, WorkflowFieldShared,
+			WorkflowFieldOwners, WorkflowFieldOnSubmit, WorkflowFieldEndRules, WorkflowFieldAutoApprove,
+			WorkflowFieldCountedVotes, WorkflowFieldGroupExclusions, WorkflowFieldUserExclusions,
+			ReviewFieldID, ReviewFieldAuthor, ReviewFieldType, ReviewFieldDescription, ReviewFieldState,
+			ReviewFieldStateLabel, ReviewFieldPending, ReviewFieldCommitStatus, ReviewFieldTestStatus,
+			ReviewFieldDeployStatus, ReviewFieldChanges, ReviewFieldCommits, ReviewFieldCreated,
+			ReviewFieldUpdated, ReviewFieldVersions,
+		)
+		So(err, ShouldBeNil)
+
+		_, err = FieldList(ProjectFieldID, ProjectField("branch"))
+		var fieldErr *InvalidFieldError
+		So(errors.As(err, &fieldErr), ShouldBeTrue)
+		So(fieldErr.Resource, ShouldEqual, "Project")
+		So(fieldErr.Field, ShouldEqual, "branch")
+	})
+}
+
+func TestWithFields_ListProjects(t *testing.T) {
+	Convey("test ListProjects with fields", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testParams(t, r, "fields=id%2Cbranches&workflow=6")
+			fmt.Fprint(w, `{"projects": [{"id": "got-dev", "branches": []}]}`)
+		})
+
+		projects, _, err := client.Projects.ListProjects(
+			&ListProjectsOptions{Fields: String("name"), Workflow: String("6")},
+			WithFields(ProjectFieldID, ProjectFieldBranches),
+		)
+		So(err, ShouldBeNil)
+		So(projects, ShouldHaveLength, 1)
+
+		_, _, err = client.Projects.ListProjects(nil, WithFields(ProjectField("unknown")))
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestWithFields_ListProjectsOptions(t *testing.T) {
+	Convey("test ListProjects with fields in the options", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+			testParams(t, r, "fields=id%2Cname")
+			fmt.Fprint(w, `{"projects": []}`)
+		})
+
+		fields, err := FieldList(ProjectFieldID, ProjectFieldName)
+		So(err, ShouldBeNil)
+		_, _, err = client.Projects.ListProjects(&ListProjectsOptions{Fields: String(fields)})
+		So(err, ShouldBeNil)
+	})
+}
+
+func TestWithFields_GetReview(t *testing.T) {
+	Convey("test GetReview with fields", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/reviews/12", func(w http.ResponseWriter, r *http.Request) {
+			testParams(t, r, "fields=id%2Cstate")
+			fmt.Fprint(w, `{"review": {"id": 12, "state": "needsReview"}}`)
+		})
+
+		review, _, err := client.Reviews.GetReview(12, WithFields(ReviewFieldID, ReviewFieldState))
+		So(err, ShouldBeNil)
+		So(review.State, ShouldEqual, "needsReview")
+	})
+}
+
+func TestWithFields_GetProject(t *testing.T) {
+	Convey("test GetProject with fields", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/projects/got-dev", func(w http.ResponseWriter, r *http.Request) {
+			testParams(t, r, "fields=id%2Cmembers")
+			fmt.Fprint(w, `{"project": {"id": "got-dev", "members": ["swarm"]}}`)
+		})
+
+		project, _, err := client.Projects.GetProject("got-dev", WithFields(ProjectFieldID, ProjectFieldMembers))
+		So(err, ShouldBeNil)
+		So(project.Members, ShouldResemble, []string{"swarm"})
+
+		_, _, err = client.Projects.GetProject("got-dev", WithFields(ProjectField("unknown")))
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestWithFields_GetWorkflow(t *testing.T) {
+	Convey("test GetWorkflow with fields", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/workflows/6", func(w http.ResponseWriter, r *http.Request) {
+			testParams(t, r, "fields=id%2Cname")
+			fmt.Fprint(w, `{"workflow": {"id": 6, "name": "Client"}}`)
+		})
+
+		workflow, _, err := client.Workflows.GetWorkflow(6, WithFields(WorkflowFieldID, WorkflowFieldName))
+		So(err, ShouldBeNil)
+		So(workflow.Name, ShouldEqual, "Client")
+	})
+}
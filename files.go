@@ -0,0 +1,206 @@
+package swarm
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// FilesService handles communication with the file related methods of the
+// Swarm API. Files are identified by their depot path, which is sent URL-safe
+// base64 encoded.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoints.html
+type FilesService struct {
+	client *Client
+}
+
+// FileVersionOptions selects the version of a file, either by revision or by
+// changelist. Without options the head revision is used.
+type FileVersionOptions struct {
+	Revision *int
+	Change   *int
+}
+
+// values returns the query parameters selecting the version of the file.
+func (o *FileVersionOptions) values() (url.Values, error) {
+	values := url.Values{}
+	if o == nil {
+		return values, nil
+	}
+	switch {
+	case o.Revision != nil && o.Change != nil:
+		return nil, errors.New("either a revision or a change can be given, not both")
+	case o.Revision != nil:
+		values.Set("fileRevision", fmt.Sprintf("#%d", *o.Revision))
+	case o.Change != nil:
+		values.Set("fileRevision", fmt.Sprintf("@%d", *o.Change))
+	}
+	return values, nil
+}
+
+// FileMetadata represents the metadata of a file revision shown in the Swarm
+// file view.
+type FileMetadata struct {
+	DepotFile string `json:"depotFile"`
+	Revision  int    `json:"revision"`
+	Change    int    `json:"change"`
+	Action    string `json:"action"`
+	Type      string `json:"type"`
+	FileSize  int64  `json:"fileSize"`
+	Digest    string `json:"digest"`
+	Time      int64  `json:"time"`
+	IsText    bool   `json:"isText"`
+}
+
+func (f FileMetadata) String() string {
+	return Stringify(f)
+}
+
+// FileRevision represents a single revision in the history of a file.
+type FileRevision struct {
+	Revision    int    `json:"revision"`
+	Change      int    `json:"change"`
+	Action      string `json:"action"`
+	Type        string `json:"type"`
+	User        string `json:"user"`
+	Client      string `json:"client"`
+	Description string `json:"description"`
+	Time        int64  `json:"time"`
+	FileSize    int64  `json:"fileSize"`
+}
+
+func (f FileRevision) String() string {
+	return Stringify(f)
+}
+
+// fileID validates the depot path of a single file and returns the ID used by
+// Swarm for the file.
+func fileID(path string) (string, error) {
+	p, err := ParseDepotPath(path)
+	if err != nil {
+		return "", err
+	}
+	if p.Exclude() || strings.Contains(p.Path(), "...") || strings.Contains(p.Path(), "*") || strings.Contains(p.Path(), "%%") {
+		return "", errors.Wrapf(ErrInvalidDepotPath, "%q is not a single file", path)
+	}
+	return base64.URLEncoding.EncodeToString([]byte(p.Path())), nil
+}
+
+// GetFileContent gets the content of a file. The content is streamed, the
+// caller has to close the returned body. Use WithProgress to report the
+// progress of reading the content.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoints.html
+func (s *FilesService) GetFileContent(path string, opt *FileVersionOptions, options ...RequestOptionFunc) (io.ReadCloser, *Response, error) {
+	id, err := fileID(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	values, err := opt.values()
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf(apiV11Path+"files/%s", PathEscape(id))
+
+	req, err := s.client.NewRequest(http.MethodGet, u, values, withEndpoint(apiV11Path+"files/{id}", options))
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Accept", "*/*")
+
+	return s.client.DoStream(req)
+}
+
+// GetFileContentCtx is like GetFileContent, but runs the request with ctx.
+func (s *FilesService) GetFileContentCtx(ctx context.Context, path string, opt *FileVersionOptions, options ...RequestOptionFunc) (io.ReadCloser, *Response, error) {
+	return s.GetFileContent(path, opt, withContextOption(ctx, options)...)
+}
+
+// GetFileMetadata gets the metadata of a file.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoints.html
+func (s *FilesService) GetFileMetadata(path string, opt *FileVersionOptions, options ...RequestOptionFunc) (*FileMetadata, *Response, error) {
+	id, err := fileID(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	values, err := opt.values()
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf(apiV11Path+"files/%s/metadata", PathEscape(id))
+
+	req, err := s.client.NewRequest(http.MethodGet, u, values, withEndpoint(apiV11Path+"files/{id}/metadata", options))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := new(struct {
+		Data *struct {
+			Metadata *FileMetadata `json:"metadata"`
+		} `json:"data"`
+	})
+	resp, err := s.client.Do(req, r)
+	if err != nil {
+		return nil, resp, err
+	}
+	if r.Data == nil {
+		return nil, resp, err
+	}
+
+	return r.Data.Metadata, resp, err
+}
+
+// GetFileMetadataCtx is like GetFileMetadata, but runs the request with ctx.
+func (s *FilesService) GetFileMetadataCtx(ctx context.Context, path string, opt *FileVersionOptions, options ...RequestOptionFunc) (*FileMetadata, *Response, error) {
+	return s.GetFileMetadata(path, opt, withContextOption(ctx, options)...)
+}
+
+// ListFileHistoryOptions represents the available ListFileHistory() options.
+type ListFileHistoryOptions struct {
+	// Max limits the number of revisions returned.
+	Max *int `url:"max,omitempty"`
+}
+
+// ListFileHistory gets the revisions of a file, newest first.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoints.html
+func (s *FilesService) ListFileHistory(path string, opt *ListFileHistoryOptions, options ...RequestOptionFunc) ([]*FileRevision, *Response, error) {
+	id, err := fileID(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf(apiV11Path+"files/%s/history", PathEscape(id))
+
+	req, err := s.client.NewRequest(http.MethodGet, u, opt, withEndpoint(apiV11Path+"files/{id}/history", options))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := new(struct {
+		Data *struct {
+			Revisions []*FileRevision `json:"revisions"`
+		} `json:"data"`
+	})
+	resp, err := s.client.Do(req, r)
+	if err != nil {
+		return nil, resp, err
+	}
+	if r.Data == nil {
+		return nil, resp, err
+	}
+
+	return r.Data.Revisions, resp, err
+}
+
+// ListFileHistoryCtx is like ListFileHistory, but runs the request with ctx.
+func (s *FilesService) ListFileHistoryCtx(ctx context.Context, path string, opt *ListFileHistoryOptions, options ...RequestOptionFunc) ([]*FileRevision, *Response, error) {
+	return s.ListFileHistory(path, opt, withContextOption(ctx, options)...)
+}
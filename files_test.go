@@ -0,0 +1,102 @@
+package swarm
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var testFileID = base64.URLEncoding.EncodeToString([]byte("//depot/main/README.md"))
+
+func TestFilesService_GetFileContent(t *testing.T) {
+	Convey("test FilesService GetFileContent", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v11/files/"+testFileID, func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testParams(t, r, "fileRevision=%233")
+			fmt.Fprint(w, "# README\n")
+		})
+
+		body, resp, err := client.Files.GetFileContent("//depot/main/README.md", &FileVersionOptions{Revision: Int(3)})
+		So(err, ShouldBeNil)
+		So(resp.StatusCode, ShouldEqual, http.StatusOK)
+		b, err := ioutil.ReadAll(body)
+		So(err, ShouldBeNil)
+		So(body.Close(), ShouldBeNil)
+		So(string(b), ShouldEqual, "# README\n")
+	})
+}
+
+func TestFilesService_GetFileMetadata(t *testing.T) {
+	Convey("test FilesService GetFileMetadata", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v11/files/"+testFileID+"/metadata", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testParams(t, r, "fileRevision=%401234")
+			fmt.Fprint(w, `{"error": null, "messages": [], "data": {"metadata": {
+				"depotFile": "//depot/main/README.md", "revision": 3, "change": 1234,
+				"action": "edit", "type": "text", "fileSize": 9, "digest": "ABCD",
+				"time": 1600000000, "isText": true}}}`)
+		})
+
+		metadata, _, err := client.Files.GetFileMetadata("//depot/main/README.md", &FileVersionOptions{Change: Int(1234)})
+		So(err, ShouldBeNil)
+		So(metadata, ShouldResemble, &FileMetadata{
+			DepotFile: "//depot/main/README.md",
+			Revision:  3,
+			Change:    1234,
+			Action:    "edit",
+			Type:      "text",
+			FileSize:  9,
+			Digest:    "ABCD",
+			Time:      1600000000,
+			IsText:    true,
+		})
+	})
+}
+
+func TestFilesService_ListFileHistory(t *testing.T) {
+	Convey("test FilesService ListFileHistory", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v11/files/"+testFileID+"/history", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testParams(t, r, "max=2")
+			fmt.Fprint(w, `{"error": null, "messages": [], "data": {"revisions": [
+				{"revision": 3, "change": 1234, "action": "edit", "user": "alice", "description": "Update readme"},
+				{"revision": 2, "change": 1200, "action": "edit", "user": "bob", "description": "Fix typo"}]}}`)
+		})
+
+		revisions, _, err := client.Files.ListFileHistory("//depot/main/README.md", &ListFileHistoryOptions{Max: Int(2)})
+		So(err, ShouldBeNil)
+		So(revisions, ShouldResemble, []*FileRevision{
+			{Revision: 3, Change: 1234, Action: "edit", User: "alice", Description: "Update readme"},
+			{Revision: 2, Change: 1200, Action: "edit", User: "bob", Description: "Fix typo"},
+		})
+	})
+}
+
+func TestFilesService_InvalidInput(t *testing.T) {
+	Convey("test FilesService with invalid input", t, func() {
+		_, server, client := setup(t)
+		defer teardown(server)
+
+		for _, path := range []string{"depot/main/README.md", "//depot/main/...", "//depot/main/*.md", "-//depot/main/README.md"} {
+			_, _, err := client.Files.GetFileMetadata(path, nil)
+			So(errors.Is(err, ErrInvalidDepotPath), ShouldBeTrue)
+		}
+
+		_, _, err := client.Files.GetFileContent("//depot/main/README.md", &FileVersionOptions{Revision: Int(1), Change: Int(2)})
+		So(err, ShouldNotBeNil)
+	})
+}
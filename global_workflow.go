@@ -0,0 +1,178 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+)
+
+// GlobalWorkflowID is the ID of the global workflow, whose rules apply to all
+// reviews and can be enforced as a policy.
+const GlobalWorkflowID = 0
+
+// swarmGroupPrefix is the prefix Swarm uses for group IDs in workflow rules
+// and reviewer lists.
+const swarmGroupPrefix = "swarm-group-"
+
+// UpdateGlobalWorkflowOptions represents the available UpdateGlobalWorkflow()
+// options. Rules which are nil are kept unchanged. Exclusions are added and
+// removed, other exclusions are kept. Groups may be given with or without the
+// swarm-group- prefix.
+type UpdateGlobalWorkflowOptions struct {
+	OnSubmit         *OnSubmit
+	EndRules         *EndRule
+	AutoApprove      *ReviewRule
+	CountedVotes     *ReviewRule
+	UserRestrictions *ReviewRule
+
+	AddGroupExclusions    []string
+	RemoveGroupExclusions []string
+	AddUserExclusions     []string
+	RemoveUserExclusions  []string
+
+	// ExclusionMode sets the mode of both exclusion rules, e.g. "policy".
+	ExclusionMode *string
+}
+
+// GetGlobalWorkflow gets the global workflow.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_workflows.html
+func (s *WorkflowService) GetGlobalWorkflow(options ...RequestOptionFunc) (*Workflow, *Response, error) {
+	return s.GetWorkflow(GlobalWorkflowID, options...)
+}
+
+// GetGlobalWorkflowCtx is like GetGlobalWorkflow, but runs the request with
+// ctx.
+func (s *WorkflowService) GetGlobalWorkflowCtx(ctx context.Context, options ...RequestOptionFunc) (*Workflow, *Response, error) {
+	return s.GetGlobalWorkflow(withContextOption(ctx, options)...)
+}
+
+// UpdateGlobalWorkflow updates the rules of the global workflow. The current
+// workflow is read first, so all rules and the description which are not
+// changed by opt are preserved.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_workflows.html
+func (s *WorkflowService) UpdateGlobalWorkflow(opt *UpdateGlobalWorkflowOptions, options ...RequestOptionFunc) (*Workflow, *Response, error) {
+	workflow, resp, err := s.GetGlobalWorkflow(options...)
+	if err != nil {
+		return nil, resp, err
+	}
+	before := *workflow
+
+	if opt != nil {
+		if err := opt.apply(workflow); err != nil {
+			return nil, resp, err
+		}
+	}
+
+	return s.putWorkflow(fmt.Sprint(GlobalWorkflowID), workflow, withAudit(&before, nil, options)...)
+}
+
+// UpdateGlobalWorkflowCtx is like UpdateGlobalWorkflow, but runs both
+// requests with ctx.
+func (s *WorkflowService) UpdateGlobalWorkflowCtx(ctx context.Context, opt *UpdateGlobalWorkflowOptions, options ...RequestOptionFunc) (*Workflow, *Response, error) {
+	return s.UpdateGlobalWorkflow(opt, withContextOption(ctx, options)...)
+}
+
+// AddGlobalExclusions adds groups and users to the exclusions of the global
+// workflow, keeping the existing exclusions.
+func (s *WorkflowService) AddGlobalExclusions(groups []string, users []string, options ...RequestOptionFunc) (*Workflow, *Response, error) {
+	return s.UpdateGlobalWorkflow(&UpdateGlobalWorkflowOptions{
+		AddGroupExclusions: groups,
+		AddUserExclusions:  users,
+	}, options...)
+}
+
+// AddGlobalExclusionsCtx is like AddGlobalExclusions, but runs both requests
+// with ctx.
+func (s *WorkflowService) AddGlobalExclusionsCtx(ctx context.Context, groups []string, users []string, options ...RequestOptionFunc) (*Workflow, *Response, error) {
+	return s.AddGlobalExclusions(groups, users, withContextOption(ctx, options)...)
+}
+
+// RemoveGlobalExclusions removes groups and users from the exclusions of the
+// global workflow, keeping the other exclusions.
+func (s *WorkflowService) RemoveGlobalExclusions(groups []string, users []string, options ...RequestOptionFunc) (*Workflow, *Response, error) {
+	return s.UpdateGlobalWorkflow(&UpdateGlobalWorkflowOptions{
+		RemoveGroupExclusions: groups,
+		RemoveUserExclusions:  users,
+	}, options...)
+}
+
+// RemoveGlobalExclusionsCtx is like RemoveGlobalExclusions, but runs both
+// requests with ctx.
+func (s *WorkflowService) RemoveGlobalExclusionsCtx(ctx context.Context, groups []string, users []string, options ...RequestOptionFunc) (*Workflow, *Response, error) {
+	return s.RemoveGlobalExclusions(groups, users, withContextOption(ctx, options)...)
+}
+
+// apply applies the options to workflow.
+func (o *UpdateGlobalWorkflowOptions) apply(workflow *Workflow) error {
+	if o.OnSubmit != nil {
+		workflow.OnSubmit = *o.OnSubmit
+	}
+	if o.EndRules != nil {
+		workflow.EndRules = *o.EndRules
+	}
+	if o.AutoApprove != nil {
+		workflow.AutoApprove = *o.AutoApprove
+	}
+	if o.CountedVotes != nil {
+		workflow.CountedVotes = *o.CountedVotes
+	}
+	if o.UserRestrictions != nil {
+		workflow.UserRestrictions = *o.UserRestrictions
+	}
+
+	if len(o.AddGroupExclusions) > 0 || len(o.RemoveGroupExclusions) > 0 {
+		groups, err := ruleList(workflow.GroupExclusion.Rule)
+		if err != nil {
+			return fmt.Errorf("group exclusions: %v", err)
+		}
+		groups, _ = addUsers(groups, withGroupPrefix(o.AddGroupExclusions))
+		groups, _ = removeUsers(groups, withGroupPrefix(o.RemoveGroupExclusions))
+		workflow.GroupExclusion.Rule = groups
+	}
+	if len(o.AddUserExclusions) > 0 || len(o.RemoveUserExclusions) > 0 {
+		users, err := ruleList(workflow.UserExclusion.Rule)
+		if err != nil {
+			return fmt.Errorf("user exclusions: %v", err)
+		}
+		users, _ = addUsers(users, o.AddUserExclusions)
+		users, _ = removeUsers(users, o.RemoveUserExclusions)
+		workflow.UserExclusion.Rule = users
+	}
+	if o.ExclusionMode != nil {
+		workflow.GroupExclusion.Mode = *o.ExclusionMode
+		workflow.UserExclusion.Mode = *o.ExclusionMode
+	}
+
+	return nil
+}
+
+// ruleList converts a rule holding a list of IDs, as decoded from JSON, to a
+// slice of strings.
+func ruleList(rule interface{}) ([]string, error) {
+	switch rule := rule.(type) {
+	case nil:
+		return []string{}, nil
+	case []string:
+		return append([]string{}, rule...), nil
+	case []interface{}:
+		list := make([]string, 0, len(rule))
+		for _, v := range rule {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("unexpected rule value %v", v)
+			}
+			list = append(list, s)
+		}
+		return list, nil
+	}
+	return nil, fmt.Errorf("unexpected rule %v", rule)
+}
+
+func withGroupPrefix(groups []string) []string {
+	result := make([]string, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, addPrefix(group, swarmGroupPrefix))
+	}
+	return result
+}
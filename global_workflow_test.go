@@ -0,0 +1,128 @@
+package swarm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const globalWorkflow = `{
+  "workflow": {
+	"id": 0,
+	"name": "Global Workflow",
+	"description": "Company policy",
+	"shared": false,
+	"owners": ["root"],
+	"on_submit": {
+	  "with_review": {"rule": "no_checking", "mode": "default"},
+	  "without_review": {"rule": "no_checking", "mode": "default"}
+	},
+	"end_rules": {"update": {"rule": "no_checking", "mode": "default"}},
+	"auto_approve": {"rule": "never", "mode": "default"},
+	"counted_votes": {"rule": "anyone", "mode": "default"},
+	"group_exclusions": {"rule": ["swarm-group-Admin", "swarm-group-QA"], "mode": "policy"},
+	"user_exclusions": {"rule": ["swarm"], "mode": "policy"},
+	"user_restrictions": []
+  }
+}`
+
+// handleGlobalWorkflow serves the global workflow and records the decoded
+// body of the update in put.
+func handleGlobalWorkflow(t *testing.T, mux *http.ServeMux, put *url.Values) {
+	mux.HandleFunc("/api/v9/workflows/0", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, globalWorkflow)
+	})
+	mux.HandleFunc("/api/v10/workflows/0", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodPut)
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("Error reading request body: %v", err)
+		}
+		if *put, err = url.ParseQuery(string(b)); err != nil {
+			t.Errorf("Error parsing request body: %v", err)
+		}
+		fmt.Fprint(w, `{"error": null, "messages": [], "data": {"workflows": [{"id": 0, "name": "Global Workflow"}]}}`)
+	})
+}
+
+func TestWorkflowService_GetGlobalWorkflow(t *testing.T) {
+	Convey("test WorkflowService_GetGlobalWorkflow", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var put url.Values
+		handleGlobalWorkflow(t, mux, &put)
+
+		workflow, _, err := client.Workflows.GetGlobalWorkflow()
+		So(err, ShouldBeNil)
+		So(workflow.Description, ShouldEqual, "Company policy")
+		So(workflow.UserRestrictions, ShouldResemble, ReviewRule{})
+		So(workflow.GroupExclusion, ShouldResemble, ReviewRule{Rule: []interface{}{"swarm-group-Admin", "swarm-group-QA"}, Mode: "policy"})
+	})
+}
+
+func TestWorkflowService_AddGlobalExclusions(t *testing.T) {
+	Convey("test WorkflowService_AddGlobalExclusions", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var put url.Values
+		handleGlobalWorkflow(t, mux, &put)
+
+		workflow, _, err := client.Workflows.AddGlobalExclusions([]string{"Dev", "swarm-group-QA"}, []string{"bot"})
+		So(err, ShouldBeNil)
+		So(workflow.Name, ShouldEqual, "Global Workflow")
+
+		So(put.Get("description"), ShouldEqual, "Company policy")
+		So(put["group_exclusions[rule][]"], ShouldResemble, []string{"swarm-group-Admin", "swarm-group-QA", "swarm-group-Dev"})
+		So(put["user_exclusions[rule][]"], ShouldResemble, []string{"swarm", "bot"})
+		So(put.Get("group_exclusions[mode]"), ShouldEqual, "policy")
+		So(put.Get("counted_votes[rule]"), ShouldEqual, "anyone")
+		So(put.Get("on_submit[with_review][rule]"), ShouldEqual, "no_checking")
+	})
+}
+
+func TestWorkflowService_RemoveGlobalExclusions(t *testing.T) {
+	Convey("test WorkflowService_RemoveGlobalExclusions", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var put url.Values
+		handleGlobalWorkflow(t, mux, &put)
+
+		_, _, err := client.Workflows.RemoveGlobalExclusions([]string{"QA"}, []string{"swarm"})
+		So(err, ShouldBeNil)
+		So(put["group_exclusions[rule][]"], ShouldResemble, []string{"swarm-group-Admin"})
+		So(put["user_exclusions[rule][]"], ShouldBeNil)
+		So(put["user_exclusions[rule]"], ShouldResemble, []string{""})
+	})
+}
+
+func TestWorkflowService_UpdateGlobalWorkflow(t *testing.T) {
+	Convey("test WorkflowService_UpdateGlobalWorkflow", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var put url.Values
+		handleGlobalWorkflow(t, mux, &put)
+
+		_, _, err := client.Workflows.UpdateGlobalWorkflow(&UpdateGlobalWorkflowOptions{
+			AutoApprove:      &ReviewRule{Rule: "votes", Mode: "policy"},
+			UserRestrictions: &ReviewRule{Rule: "reject", Mode: "policy"},
+			ExclusionMode:    String("default"),
+		})
+		So(err, ShouldBeNil)
+		So(put.Get("description"), ShouldEqual, "Company policy")
+		So(put.Get("auto_approve[rule]"), ShouldEqual, "votes")
+		So(put.Get("auto_approve[mode]"), ShouldEqual, "policy")
+		So(put.Get("user_restrictions[rule]"), ShouldEqual, "reject")
+		So(put.Get("group_exclusions[mode]"), ShouldEqual, "default")
+		So(put["group_exclusions[rule][]"], ShouldResemble, []string{"swarm-group-Admin", "swarm-group-QA"})
+		So(put.Get("end_rules[update][rule]"), ShouldEqual, "no_checking")
+	})
+}
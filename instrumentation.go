@@ -0,0 +1,112 @@
+package swarm
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+// Instrumenter describes the interface that all (custom) instrumentations
+// must implement. RequestStart is called once before a request is sent and
+// may return a derived context, e.g. one carrying a tracing span, which is
+// used for the request and passed to RequestEnd once the request is done.
+type Instrumenter interface {
+	RequestStart(ctx context.Context, info *RequestInfo) context.Context
+	RequestEnd(ctx context.Context, info *RequestInfo)
+}
+
+// RequestInfo describes a single API call made through Client.Do.
+type RequestInfo struct {
+	// Method is the HTTP method of the request.
+	Method string
+
+	// Endpoint is the endpoint template of the request, e.g. projects/{id}.
+	Endpoint string
+
+	// URL is the full URL of the request.
+	URL string
+
+	// Start is the time the request was started.
+	Start time.Time
+
+	// The fields below are only set when RequestEnd is called.
+
+	// StatusCode is the HTTP status code of the response, or zero when no
+	// response was received.
+	StatusCode int
+
+	// Retries is the number of times the request was retried.
+	Retries int
+
+	// LimiterWait is the time spent waiting for the rate limiter.
+	LimiterWait time.Duration
+
+	// Duration is the total duration of the call.
+	Duration time.Duration
+
+	// Err is the error returned by the call, if any.
+	Err error
+}
+
+// requestInfoKey is the context key used to store the RequestInfo.
+type requestInfoKey struct{}
+
+// withEndpoint prepends an option setting the endpoint template used for
+// instrumentation to options.
+func withEndpoint(endpoint string, options []RequestOptionFunc) []RequestOptionFunc {
+	return append([]RequestOptionFunc{withRequestConfig(func(cfg *requestConfig) {
+		cfg.endpoint = endpoint
+	})}, options...)
+}
+
+// endpoint returns the endpoint template of req. When no template was set by
+// the service method, the relative path of the request is used instead.
+func (c *Client) endpoint(req *retryablehttp.Request) string {
+	if endpoint := requestConfigFrom(req.Context()).endpoint; endpoint != "" {
+		return endpoint
+	}
+	path := strings.TrimPrefix(req.URL.Path, c.baseURL.Path)
+	// Strip the server the request is scoped to, if any.
+	if i := strings.Index(path, apiPrefix); i > 0 {
+		path = path[i:]
+	}
+	return strings.TrimPrefix(path, apiV9Path)
+}
+
+// instrument runs the request through the configured Instrumenter.
+func (c *Client) instrument(req *retryablehttp.Request, v interface{}) (*Response, error) {
+	info := &RequestInfo{
+		Method:   req.Method,
+		Endpoint: c.endpoint(req),
+		URL:      req.URL.String(),
+		Start:    time.Now(),
+	}
+
+	ctx := c.instrumenter.RequestStart(req.Context(), info)
+	req = req.WithContext(context.WithValue(ctx, requestInfoKey{}, info))
+
+	resp, err := c.do(req, v)
+
+	info.Duration = time.Since(info.Start)
+	if resp != nil {
+		info.StatusCode = resp.StatusCode
+	}
+	info.Err = err
+	c.instrumenter.RequestEnd(ctx, info)
+
+	return resp, err
+}
+
+// requestLogHook provides a callback for Client.RequestLogHook which records
+// the number of retries and calls the custom request log hook, if any.
+func (c *Client) requestLogHook(logger retryablehttp.Logger, req *http.Request, attemptNum int) {
+	if info, ok := req.Context().Value(requestInfoKey{}).(*RequestInfo); ok {
+		info.Retries = attemptNum
+	}
+	if c.customRequestLogHook != nil {
+		c.customRequestLogHook(logger, req, attemptNum)
+	}
+}
@@ -0,0 +1,25 @@
+module github.com/eyotang/go-swarm/instrumentation/swarmotel
+
+go 1.20
+
+require (
+	github.com/eyotang/go-swarm v0.0.0
+	go.opentelemetry.io/otel v1.24.0
+	go.opentelemetry.io/otel/metric v1.24.0
+	go.opentelemetry.io/otel/sdk v1.24.0
+	go.opentelemetry.io/otel/trace v1.24.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.1 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/go-querystring v1.1.0 // indirect
+	github.com/hashicorp/go-cleanhttp v0.5.2 // indirect
+	github.com/hashicorp/go-retryablehttp v0.7.1 // indirect
+	github.com/hetiansu5/urlquery v1.2.7 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	golang.org/x/sys v0.17.0 // indirect
+	golang.org/x/time v0.0.0-20220411224347-583f2d630306 // indirect
+)
+
+replace github.com/eyotang/go-swarm => ../..
@@ -0,0 +1,90 @@
+// Package swarmotel provides an OpenTelemetry instrumentation for the Swarm
+// API client.
+package swarmotel
+
+import (
+	"context"
+
+	swarm "github.com/eyotang/go-swarm"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName is the name used for the tracer and meter.
+const instrumentationName = "github.com/eyotang/go-swarm/instrumentation/swarmotel"
+
+// Instrumenter creates a client span and records the duration of every Swarm
+// API call. It implements the swarm.Instrumenter interface.
+type Instrumenter struct {
+	tracer      trace.Tracer
+	duration    metric.Float64Histogram
+	limiterWait metric.Float64Histogram
+}
+
+// New returns a new Instrumenter using the given tracer and meter providers.
+func New(tp trace.TracerProvider, mp metric.MeterProvider) (*Instrumenter, error) {
+	meter := mp.Meter(instrumentationName)
+
+	duration, err := meter.Float64Histogram(
+		"swarm.client.request.duration",
+		metric.WithUnit("s"),
+		metric.WithDescription("Duration of Swarm API calls including retries."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	limiterWait, err := meter.Float64Histogram(
+		"swarm.client.rate_limiter.wait",
+		metric.WithUnit("s"),
+		metric.WithDescription("Time spent waiting for the client rate limiter."),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Instrumenter{
+		tracer:      tp.Tracer(instrumentationName),
+		duration:    duration,
+		limiterWait: limiterWait,
+	}, nil
+}
+
+// RequestStart implements swarm.Instrumenter.
+func (i *Instrumenter) RequestStart(ctx context.Context, info *swarm.RequestInfo) context.Context {
+	ctx, _ = i.tracer.Start(ctx, info.Method+" "+info.Endpoint,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(info.Start),
+		trace.WithAttributes(
+			attribute.String("http.request.method", info.Method),
+			attribute.String("url.full", info.URL),
+			attribute.String("swarm.endpoint", info.Endpoint),
+		),
+	)
+	return ctx
+}
+
+// RequestEnd implements swarm.Instrumenter.
+func (i *Instrumenter) RequestEnd(ctx context.Context, info *swarm.RequestInfo) {
+	attrs := []attribute.KeyValue{
+		attribute.String("http.request.method", info.Method),
+		attribute.String("swarm.endpoint", info.Endpoint),
+		attribute.Int("http.response.status_code", info.StatusCode),
+	}
+	i.duration.Record(ctx, info.Duration.Seconds(), metric.WithAttributes(attrs...))
+	i.limiterWait.Record(ctx, info.LimiterWait.Seconds(), metric.WithAttributes(attrs[:2]...))
+
+	span := trace.SpanFromContext(ctx)
+	span.SetAttributes(
+		attribute.Int("http.response.status_code", info.StatusCode),
+		attribute.Int("http.request.resend_count", info.Retries),
+		attribute.Float64("swarm.rate_limiter.wait", info.LimiterWait.Seconds()),
+	)
+	if info.Err != nil {
+		span.RecordError(info.Err)
+		span.SetStatus(codes.Error, info.Err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,41 @@
+package swarmotel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	swarm "github.com/eyotang/go-swarm"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric/noop"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInstrumenter(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	i, err := New(tp, noop.NewMeterProvider())
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	info := &swarm.RequestInfo{Method: http.MethodGet, Endpoint: "projects/{id}"}
+	ctx := i.RequestStart(context.Background(), info)
+	info.StatusCode = http.StatusNotFound
+	info.Err = errors.New("not found")
+	i.RequestEnd(ctx, info)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if got, want := spans[0].Name(), "GET projects/{id}"; got != want {
+		t.Errorf("span name is %q, want %q", got, want)
+	}
+	if got := spans[0].Status().Code; got != codes.Error {
+		t.Errorf("span status is %v, want %v", got, codes.Error)
+	}
+}
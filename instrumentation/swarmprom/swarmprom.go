@@ -0,0 +1,80 @@
+// Package swarmprom provides a Prometheus instrumentation for the Swarm API
+// client.
+package swarmprom
+
+import (
+	"context"
+	"strconv"
+
+	swarm "github.com/eyotang/go-swarm"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Instrumenter collects Prometheus metrics for all Swarm API calls. It
+// implements the swarm.Instrumenter interface.
+type Instrumenter struct {
+	requests    *prometheus.CounterVec
+	duration    *prometheus.HistogramVec
+	retries     *prometheus.CounterVec
+	limiterWait *prometheus.HistogramVec
+}
+
+// New returns a new Instrumenter and registers its metrics with reg.
+func New(reg prometheus.Registerer) (*Instrumenter, error) {
+	labels := []string{"method", "endpoint"}
+
+	i := &Instrumenter{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "swarm",
+			Subsystem: "client",
+			Name:      "requests_total",
+			Help:      "Total number of Swarm API calls by endpoint and status.",
+		}, append(labels, "status")),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "swarm",
+			Subsystem: "client",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of Swarm API calls including retries.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "swarm",
+			Subsystem: "client",
+			Name:      "request_retries_total",
+			Help:      "Total number of retried Swarm API requests.",
+		}, labels),
+		limiterWait: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "swarm",
+			Subsystem: "client",
+			Name:      "rate_limiter_wait_seconds",
+			Help:      "Time spent waiting for the client rate limiter.",
+			Buckets:   prometheus.DefBuckets,
+		}, labels),
+	}
+
+	for _, c := range []prometheus.Collector{i.requests, i.duration, i.retries, i.limiterWait} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+
+	return i, nil
+}
+
+// RequestStart implements swarm.Instrumenter.
+func (i *Instrumenter) RequestStart(ctx context.Context, info *swarm.RequestInfo) context.Context {
+	return ctx
+}
+
+// RequestEnd implements swarm.Instrumenter.
+func (i *Instrumenter) RequestEnd(ctx context.Context, info *swarm.RequestInfo) {
+	status := "error"
+	if info.StatusCode != 0 {
+		status = strconv.Itoa(info.StatusCode)
+	}
+
+	i.requests.WithLabelValues(info.Method, info.Endpoint, status).Inc()
+	i.duration.WithLabelValues(info.Method, info.Endpoint).Observe(info.Duration.Seconds())
+	i.retries.WithLabelValues(info.Method, info.Endpoint).Add(float64(info.Retries))
+	i.limiterWait.WithLabelValues(info.Method, info.Endpoint).Observe(info.LimiterWait.Seconds())
+}
@@ -0,0 +1,41 @@
+package swarmprom
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	swarm "github.com/eyotang/go-swarm"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestInstrumenter(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	i, err := New(reg)
+	if err != nil {
+		t.Fatalf("New returned error: %v", err)
+	}
+
+	info := &swarm.RequestInfo{
+		Method:     http.MethodGet,
+		Endpoint:   "projects/{id}",
+		StatusCode: http.StatusOK,
+		Retries:    2,
+		Duration:   time.Second,
+	}
+	ctx := i.RequestStart(context.Background(), info)
+	i.RequestEnd(ctx, info)
+
+	if got := testutil.ToFloat64(i.requests.WithLabelValues(http.MethodGet, "projects/{id}", "200")); got != 1 {
+		t.Errorf("requests_total is %v, want 1", got)
+	}
+	if got := testutil.ToFloat64(i.retries.WithLabelValues(http.MethodGet, "projects/{id}")); got != 2 {
+		t.Errorf("request_retries_total is %v, want 2", got)
+	}
+
+	if _, err := New(reg); err == nil {
+		t.Errorf("New expected an error when registering twice")
+	}
+}
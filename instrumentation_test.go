@@ -0,0 +1,84 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+type testInstrumenter struct {
+	started []RequestInfo
+	ended   []RequestInfo
+}
+
+func (i *testInstrumenter) RequestStart(ctx context.Context, info *RequestInfo) context.Context {
+	i.started = append(i.started, *info)
+	return ctx
+}
+
+func (i *testInstrumenter) RequestEnd(ctx context.Context, info *RequestInfo) {
+	i.ended = append(i.ended, *info)
+}
+
+func TestClient_Instrumenter(t *testing.T) {
+	Convey("test Client_Instrumenter", t, func() {
+		instrumenter := new(testInstrumenter)
+		var attempts []int
+		mux, server, client := setup(t,
+			WithInstrumenter(instrumenter),
+			WithCustomBackoff(func(min, max time.Duration, attemptNum int, resp *http.Response) time.Duration {
+				return time.Millisecond
+			}),
+			WithRequestLogHook(func(_ retryablehttp.Logger, _ *http.Request, attemptNum int) {
+				attempts = append(attempts, attemptNum)
+			}),
+		)
+		defer teardown(server)
+
+		var hits int
+		mux.HandleFunc("/api/v9/projects/got-dev", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			hits++
+			if hits == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			fmt.Fprint(w, `{"project": {"id": "got-dev", "name": "Got-dev"}}`)
+		})
+		mux.HandleFunc("/api/v9/workflows", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error": "Not Found"}`)
+		})
+
+		_, _, err := client.Projects.GetProject("got-dev")
+		So(err, ShouldBeNil)
+
+		_, _, err = client.Workflows.ListWorkflows(nil)
+		So(err, ShouldNotBeNil)
+
+		So(attempts, ShouldResemble, []int{0, 1, 0})
+		So(instrumenter.started, ShouldHaveLength, 2)
+		So(instrumenter.ended, ShouldHaveLength, 2)
+
+		info := instrumenter.ended[0]
+		So(info.Method, ShouldEqual, http.MethodGet)
+		So(info.Endpoint, ShouldEqual, "projects/{id}")
+		So(info.URL, ShouldEqual, server.URL+"/api/v9/projects/got-dev")
+		So(info.StatusCode, ShouldEqual, http.StatusOK)
+		So(info.Retries, ShouldEqual, 1)
+		So(info.Duration, ShouldBeGreaterThan, 0)
+		So(info.Err, ShouldBeNil)
+
+		info = instrumenter.ended[1]
+		So(info.Endpoint, ShouldEqual, "workflows")
+		So(info.StatusCode, ShouldEqual, http.StatusNotFound)
+		So(info.Retries, ShouldEqual, 0)
+		So(info.Err, ShouldNotBeNil)
+	})
+}
@@ -0,0 +1,263 @@
+package swarm
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"golang.org/x/time/rate"
+)
+
+const (
+	headerRateRemaining = "RateLimit-Remaining"
+	headerRetryAfter    = "Retry-After"
+)
+
+// RateLimitObserver can be implemented by a RateLimiter to receive every
+// response (including retried attempts) returned by Swarm. Responses are
+// observed independent of the retry policy and circuit breaker.
+type RateLimitObserver interface {
+	ObserveResponse(*http.Response)
+}
+
+// LimiterState describes the current state of an AdaptiveLimiter.
+type LimiterState struct {
+	// RequestsPerMinute is the last rate limit reported by Swarm, or zero
+	// when Swarm did not report a rate limit (yet).
+	RequestsPerMinute float64
+
+	// Limit and Burst are the values currently used by the limiter.
+	Limit rate.Limit
+	Burst int
+
+	// Remaining is the last number of remaining requests reported by Swarm,
+	// or -1 when unknown.
+	Remaining int
+
+	// PausedUntil is set when Swarm asked to pause all requests.
+	PausedUntil time.Time
+
+	// UpdatedAt is the time the limiter was last adjusted.
+	UpdatedAt time.Time
+}
+
+// AdaptiveLimiter is a RateLimiter which adjusts its limit and burst to the
+// rate limit headers of every response, and pauses all requests when Swarm
+// responds with a Retry-After header or has no remaining requests left.
+type AdaptiveLimiter struct {
+	limiter *rate.Limiter
+
+	mu    sync.Mutex
+	state LimiterState
+}
+
+// NewAdaptiveLimiter returns a new AdaptiveLimiter. The limiter does not
+// limit any requests until Swarm reports a rate limit.
+func NewAdaptiveLimiter() *AdaptiveLimiter {
+	return &AdaptiveLimiter{
+		limiter: rate.NewLimiter(rate.Inf, 0),
+		state: LimiterState{
+			Limit:     rate.Inf,
+			Remaining: -1,
+		},
+	}
+}
+
+// maxSharedLimiters bounds the number of shared AdaptiveLimiters. When more
+// host and user combinations are used, the least recently used limiter is no
+// longer shared with new clients.
+const maxSharedLimiters = 256
+
+var (
+	// adaptiveLimitersLock protects the adaptiveLimiters map.
+	adaptiveLimitersLock sync.Mutex
+
+	// adaptiveLimiters holds the shared limiters per host and user.
+	adaptiveLimiters = make(map[adaptiveLimiterKey]*sharedLimiter)
+)
+
+// adaptiveLimiterKey identifies the clients sharing an AdaptiveLimiter. Swarm
+// rate limits each user, so clients of different users do not throttle each
+// other.
+type adaptiveLimiterKey struct {
+	host string
+	user string
+}
+
+// sharedLimiter is an AdaptiveLimiter with the time it was last handed out.
+type sharedLimiter struct {
+	limiter *AdaptiveLimiter
+	used    time.Time
+}
+
+// sharedAdaptiveLimiter returns the AdaptiveLimiter shared by all clients
+// with the given key.
+func sharedAdaptiveLimiter(key adaptiveLimiterKey) *AdaptiveLimiter {
+	adaptiveLimitersLock.Lock()
+	defer adaptiveLimitersLock.Unlock()
+
+	now := time.Now()
+	if l, ok := adaptiveLimiters[key]; ok {
+		l.used = now
+		return l.limiter
+	}
+
+	if len(adaptiveLimiters) >= maxSharedLimiters {
+		var oldest adaptiveLimiterKey
+		var used time.Time
+		for k, l := range adaptiveLimiters {
+			if used.IsZero() || l.used.Before(used) {
+				oldest, used = k, l.used
+			}
+		}
+		delete(adaptiveLimiters, oldest)
+	}
+
+	l := &sharedLimiter{limiter: NewAdaptiveLimiter(), used: now}
+	adaptiveLimiters[key] = l
+	return l.limiter
+}
+
+// rateLimiter returns the rate limiter of the client. Unless a custom limiter
+// was configured, the client uses the AdaptiveLimiter shared with the other
+// clients of the same user and host.
+func (c *Client) rateLimiter() RateLimiter {
+	c.configureLimiterOnce.Do(func() {
+		c.limiter = sharedAdaptiveLimiter(adaptiveLimiterKey{host: c.baseURL.Host, user: c.username})
+	})
+	return c.limiter
+}
+
+// responseLogHook provides a callback for Client.ResponseLogHook which passes
+// the response of every attempt to the rate limiter, if it observes responses,
+// before calling the response log hook configured by the user.
+func (c *Client) responseLogHook(logger retryablehttp.Logger, resp *http.Response) {
+	if o, ok := c.rateLimiter().(RateLimitObserver); ok && resp != nil {
+		o.ObserveResponse(resp)
+	}
+	if c.customResponseLogHook != nil {
+		c.customResponseLogHook(logger, resp)
+	}
+}
+
+// Wait blocks until a request may be sent or ctx is done.
+func (l *AdaptiveLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	pausedUntil := l.state.PausedUntil
+	l.mu.Unlock()
+
+	if wait := time.Until(pausedUntil); wait > 0 {
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	return l.limiter.Wait(ctx)
+}
+
+// ObserveResponse adjusts the limiter to the rate limit headers of resp.
+func (l *AdaptiveLimiter) ObserveResponse(resp *http.Response) {
+	if resp == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+
+	if v := rateLimitHeader(resp.Header, headerRateLimit); v != "" {
+		if rateLimit, _ := strconv.ParseFloat(v, 64); rateLimit > 0 && rateLimit != l.state.RequestsPerMinute {
+			// The rate limit is based on requests per minute. Use 2/3 of it as
+			// the limit and 1/3 as the burst, so clients can burst 1/3 of the
+			// allowed calls and the remaining calls are spread out evenly.
+			perSecond := rateLimit / 60
+			limit := rate.Limit(perSecond * 0.66)
+			burst := int(perSecond * 0.33)
+			if burst < 1 {
+				burst = 1
+			}
+
+			l.limiter.SetLimitAt(now, limit)
+			l.limiter.SetBurstAt(now, burst)
+
+			l.state.RequestsPerMinute = rateLimit
+			l.state.Limit = limit
+			l.state.Burst = burst
+			l.state.UpdatedAt = now
+		}
+	}
+
+	if v := rateLimitHeader(resp.Header, headerRateRemaining); v != "" {
+		if remaining, err := strconv.Atoi(v); err == nil {
+			l.state.Remaining = remaining
+			if remaining == 0 {
+				l.pauseUntil(parseRateReset(rateLimitHeader(resp.Header, headerRateReset)), now)
+			}
+		}
+	}
+
+	if v := resp.Header.Get(headerRetryAfter); v != "" {
+		l.pauseUntil(parseRetryAfter(v, now), now)
+	}
+}
+
+// pauseUntil pauses all requests until t. The caller must hold the lock.
+func (l *AdaptiveLimiter) pauseUntil(t, now time.Time) {
+	if t.After(now) && t.After(l.state.PausedUntil) {
+		l.state.PausedUntil = t
+		l.state.UpdatedAt = now
+	}
+}
+
+// State returns the current state of the limiter.
+func (l *AdaptiveLimiter) State() LimiterState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.state
+}
+
+// LimiterState returns the state of the rate limiter of the client, if the
+// client uses an AdaptiveLimiter.
+func (c *Client) LimiterState() (LimiterState, bool) {
+	if l, ok := c.rateLimiter().(*AdaptiveLimiter); ok {
+		return l.State(), true
+	}
+	return LimiterState{}, false
+}
+
+// rateLimitHeader returns the rate limit header name, or its X- prefixed
+// variant, e.g. X-RateLimit-Limit.
+func rateLimitHeader(h http.Header, name string) string {
+	if v := h.Get(name); v != "" {
+		return v
+	}
+	return h.Get("X-" + name)
+}
+
+// parseRateReset parses the RateLimit-Reset header, a unix timestamp.
+func parseRateReset(v string) time.Time {
+	if reset, _ := strconv.ParseInt(v, 10, 64); reset > 0 {
+		return time.Unix(reset, 0)
+	}
+	return time.Time{}
+}
+
+// parseRetryAfter parses the Retry-After header, which is either a number of
+// seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) time.Time {
+	if seconds, err := strconv.Atoi(v); err == nil {
+		return now.Add(time.Duration(seconds) * time.Second)
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return t
+	}
+	return time.Time{}
+}
@@ -0,0 +1,153 @@
+package swarm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	. "github.com/smartystreets/goconvey/convey"
+	"golang.org/x/time/rate"
+)
+
+func TestAdaptiveLimiter_ObserveResponse(t *testing.T) {
+	Convey("test AdaptiveLimiter_ObserveResponse", t, func() {
+		l := NewAdaptiveLimiter()
+		So(l.State().Limit, ShouldEqual, rate.Inf)
+		So(l.State().Remaining, ShouldEqual, -1)
+
+		resp := &http.Response{Header: make(http.Header)}
+		resp.Header.Set(headerRateLimit, "600")
+		resp.Header.Set(headerRateRemaining, "42")
+		l.ObserveResponse(resp)
+
+		state := l.State()
+		So(state.RequestsPerMinute, ShouldEqual, 600)
+		So(state.Limit, ShouldAlmostEqual, 6.6)
+		So(state.Burst, ShouldEqual, 3)
+		So(state.Remaining, ShouldEqual, 42)
+		So(state.PausedUntil.IsZero(), ShouldBeTrue)
+
+		resp.Header.Set(headerRateLimit, "60")
+		resp.Header.Set(headerRetryAfter, "30")
+		l.ObserveResponse(resp)
+
+		state = l.State()
+		So(state.RequestsPerMinute, ShouldEqual, 60)
+		So(state.Burst, ShouldEqual, 1)
+		So(state.PausedUntil, ShouldHappenAfter, time.Now().Add(29*time.Second))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		So(errors.Is(l.Wait(ctx), context.DeadlineExceeded), ShouldBeTrue)
+	})
+}
+
+func TestAdaptiveLimiter_RemainingReset(t *testing.T) {
+	Convey("test AdaptiveLimiter_RemainingReset", t, func() {
+		l := NewAdaptiveLimiter()
+
+		reset := time.Now().Add(time.Minute).Unix()
+		resp := &http.Response{Header: make(http.Header)}
+		resp.Header.Set(headerRateRemaining, "0")
+		resp.Header.Set(headerRateReset, strconv.FormatInt(reset, 10))
+		l.ObserveResponse(resp)
+
+		So(l.State().Remaining, ShouldEqual, 0)
+		So(l.State().PausedUntil, ShouldEqual, time.Unix(reset, 0))
+	})
+}
+
+func TestClient_AdaptiveLimiter(t *testing.T) {
+	Convey("test Client_AdaptiveLimiter", t, func() {
+		mux, server, _ := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			w.Header().Set("X-"+headerRateLimit, "6000")
+			w.Header().Set("X-"+headerRateRemaining, "5999")
+			fmt.Fprint(w, `{"projects": []}`)
+		})
+
+		// The adaptive limiter is used by default and shared per host and user.
+		client, err := NewBasicAuthClient("username", "password", WithBaseURL(server.URL))
+		So(err, ShouldBeNil)
+		other, err := NewBasicAuthClient("username", "password", WithBaseURL(server.URL))
+		So(err, ShouldBeNil)
+		So(other.rateLimiter(), ShouldEqual, client.rateLimiter())
+
+		_, _, err = client.Projects.ListProjects(nil)
+		So(err, ShouldBeNil)
+
+		state, ok := other.LimiterState()
+		So(ok, ShouldBeTrue)
+		So(state.RequestsPerMinute, ShouldEqual, 6000)
+		So(state.Remaining, ShouldEqual, 5999)
+
+		custom, err := NewBasicAuthClient("username", "password", WithBaseURL(server.URL),
+			WithCustomLimiter(rate.NewLimiter(rate.Inf, 0)))
+		So(err, ShouldBeNil)
+		_, ok = custom.LimiterState()
+		So(ok, ShouldBeFalse)
+
+		// Clients of other users are not throttled by the shared limiter.
+		stranger, err := NewBasicAuthClient("stranger", "password", WithBaseURL(server.URL))
+		So(err, ShouldBeNil)
+		So(stranger.rateLimiter(), ShouldNotEqual, client.rateLimiter())
+		state, _ = stranger.LimiterState()
+		So(state.RequestsPerMinute, ShouldEqual, 0)
+	})
+}
+
+func TestSharedAdaptiveLimiter_Bounded(t *testing.T) {
+	Convey("test sharedAdaptiveLimiter evicting the least recently used limiter", t, func() {
+		first := sharedAdaptiveLimiter(adaptiveLimiterKey{host: "bounded.url", user: "first"})
+		for i := 0; i < maxSharedLimiters; i++ {
+			sharedAdaptiveLimiter(adaptiveLimiterKey{host: "bounded.url", user: strconv.Itoa(i)})
+		}
+
+		adaptiveLimitersLock.Lock()
+		size := len(adaptiveLimiters)
+		adaptiveLimitersLock.Unlock()
+		So(size, ShouldBeLessThanOrEqualTo, maxSharedLimiters)
+		So(sharedAdaptiveLimiter(adaptiveLimiterKey{host: "bounded.url", user: "first"}), ShouldNotEqual, first)
+	})
+}
+
+func TestClient_AdaptiveLimiterObservesResponses(t *testing.T) {
+	Convey("test Client_AdaptiveLimiter with a custom retry policy and response log hook", t, func() {
+		mux, server, _ := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set(headerRateLimit, "600")
+			w.Header().Set(headerRateRemaining, "42")
+			fmt.Fprint(w, `{"projects": []}`)
+		})
+
+		hooked := 0
+		client, err := NewBasicAuthClient("observed", "password", WithBaseURL(server.URL),
+			WithCustomRetry(func(ctx context.Context, resp *http.Response, err error) (bool, error) {
+				return false, err
+			}),
+			WithResponseLogHook(func(logger retryablehttp.Logger, resp *http.Response) {
+				hooked++
+			}),
+		)
+		So(err, ShouldBeNil)
+
+		_, _, err = client.Projects.ListProjects(nil)
+		So(err, ShouldBeNil)
+		So(hooked, ShouldEqual, 1)
+
+		state, ok := client.LimiterState()
+		So(ok, ShouldBeTrue)
+		So(state.RequestsPerMinute, ShouldEqual, 600)
+		So(state.Remaining, ShouldEqual, 42)
+	})
+}
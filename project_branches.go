@@ -0,0 +1,186 @@
+package swarm
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/pkg/errors"
+)
+
+// ErrBranchNotFound is returned when a project has no branch with the
+// requested ID.
+var ErrBranchNotFound = errors.New("branch not found")
+
+// ErrBranchExists is returned when adding a branch with an ID which is
+// already used by the project.
+var ErrBranchExists = errors.New("branch already exists")
+
+// findBranch returns the index of the branch with the given ID, or -1.
+func findBranch(p *Project, branchID string) int {
+	for i, b := range p.Branches {
+		if b.ID == branchID {
+			return i
+		}
+	}
+	return -1
+}
+
+// GetBranch gets a single branch of a project.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_projects.html
+func (s *ProjectsService) GetBranch(pid interface{}, branchID string, options ...RequestOptionFunc) (*Branch, *Response, error) {
+	project, resp, err := s.GetProject(pid, options...)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	i := findBranch(project, branchID)
+	if i < 0 {
+		return nil, resp, errors.Wrapf(ErrBranchNotFound, "project %q, branch %q", project.ID, branchID)
+	}
+
+	return &project.Branches[i], resp, nil
+}
+
+// GetBranchCtx is like GetBranch, but runs the request with ctx.
+func (s *ProjectsService) GetBranchCtx(ctx context.Context, pid interface{}, branchID string, options ...RequestOptionFunc) (*Branch, *Response, error) {
+	return s.GetBranch(pid, branchID, withContextOption(ctx, options)...)
+}
+
+// AddBranch adds a branch to a project. The ID of the branch is required, all
+// other branches are sent unchanged.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_projects.html
+func (s *ProjectsService) AddBranch(pid interface{}, opt *BranchOptions, options ...RequestOptionFunc) (*Project, *Response, error) {
+	if opt == nil || opt.ID == nil || *opt.ID == "" {
+		return nil, nil, errors.New("branch ID is required")
+	}
+	if err := opt.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return s.modifyProject(pid, func(p *Project) (*UpdateProjectOptions, error) {
+		if findBranch(p, *opt.ID) >= 0 {
+			return nil, errors.Wrapf(ErrBranchExists, "project %q, branch %q", p.ID, *opt.ID)
+		}
+		branches := make([]*BranchOptions, 0, len(p.Branches)+1)
+		for _, b := range p.Branches {
+			branches = append(branches, newBranchOptions(b))
+		}
+		return &UpdateProjectOptions{Branches: append(branches, opt)}, nil
+	}, func(p *Project) bool {
+		return findBranch(p, *opt.ID) >= 0
+	}, options)
+}
+
+// AddBranchCtx is like AddBranch, but runs the requests with ctx.
+func (s *ProjectsService) AddBranchCtx(ctx context.Context, pid interface{}, opt *BranchOptions, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.AddBranch(pid, opt, withContextOption(ctx, options)...)
+}
+
+// UpdateBranch updates a single branch of a project. Fields of opt which are
+// nil keep their current value, all other branches are sent unchanged.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_projects.html
+func (s *ProjectsService) UpdateBranch(pid interface{}, branchID string, opt *BranchOptions, options ...RequestOptionFunc) (*Project, *Response, error) {
+	if err := opt.validate(); err != nil {
+		return nil, nil, err
+	}
+
+	return s.modifyProject(pid, func(p *Project) (*UpdateProjectOptions, error) {
+		if findBranch(p, branchID) < 0 {
+			return nil, errors.Wrapf(ErrBranchNotFound, "project %q, branch %q", p.ID, branchID)
+		}
+		branches := make([]*BranchOptions, 0, len(p.Branches))
+		for _, b := range p.Branches {
+			current := newBranchOptions(b)
+			if b.ID == branchID {
+				current = mergeBranchOptions(current, opt)
+			}
+			branches = append(branches, current)
+		}
+		return &UpdateProjectOptions{Branches: branches}, nil
+	}, func(p *Project) bool {
+		i := findBranch(p, branchID)
+		return i >= 0 && branchApplied(newBranchOptions(p.Branches[i]), opt)
+	}, options)
+}
+
+// UpdateBranchCtx is like UpdateBranch, but runs the requests with ctx.
+func (s *ProjectsService) UpdateBranchCtx(ctx context.Context, pid interface{}, branchID string, opt *BranchOptions, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.UpdateBranch(pid, branchID, opt, withContextOption(ctx, options)...)
+}
+
+// RemoveBranch removes a single branch from a project, all other branches are
+// sent unchanged.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_projects.html
+func (s *ProjectsService) RemoveBranch(pid interface{}, branchID string, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.modifyProject(pid, func(p *Project) (*UpdateProjectOptions, error) {
+		if findBranch(p, branchID) < 0 {
+			return nil, errors.Wrapf(ErrBranchNotFound, "project %q, branch %q", p.ID, branchID)
+		}
+		branches := make([]*BranchOptions, 0, len(p.Branches))
+		for _, b := range p.Branches {
+			if b.ID != branchID {
+				branches = append(branches, newBranchOptions(b))
+			}
+		}
+		return &UpdateProjectOptions{Branches: branches}, nil
+	}, func(p *Project) bool {
+		return findBranch(p, branchID) < 0
+	}, options)
+}
+
+// RemoveBranchCtx is like RemoveBranch, but runs the requests with ctx.
+func (s *ProjectsService) RemoveBranchCtx(ctx context.Context, pid interface{}, branchID string, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.RemoveBranch(pid, branchID, withContextOption(ctx, options)...)
+}
+
+// mergeBranchOptions returns current with all fields set in opt replaced.
+// The ID of the branch is never changed.
+func mergeBranchOptions(current, opt *BranchOptions) *BranchOptions {
+	if opt == nil {
+		return current
+	}
+	merged := *current
+	if opt.Name != nil {
+		merged.Name = opt.Name
+	}
+	if opt.Workflow != nil {
+		merged.Workflow = opt.Workflow
+	}
+	if opt.Paths != nil {
+		merged.Paths = opt.Paths
+	}
+	if opt.Defaults != nil {
+		merged.Defaults = opt.Defaults
+	}
+	if opt.Moderators != nil {
+		merged.Moderators = opt.Moderators
+	}
+	if opt.ModeratorGroups != nil {
+		merged.ModeratorGroups = opt.ModeratorGroups
+	}
+	if opt.MinimumUpVotes != nil {
+		merged.MinimumUpVotes = opt.MinimumUpVotes
+	}
+	if opt.RetainDefaultReviewers != nil {
+		merged.RetainDefaultReviewers = opt.RetainDefaultReviewers
+	}
+	return &merged
+}
+
+// branchApplied reports whether the fields set in opt are present in current.
+// Default reviewers are not compared, Swarm does not return them in the form
+// they are sent in.
+func branchApplied(current, opt *BranchOptions) bool {
+	want := mergeBranchOptions(current, opt)
+	want.Defaults = current.Defaults
+	if opt != nil && opt.Paths != nil {
+		if ps, err := ParseDepotPaths(stringValues(opt.Paths)); err == nil {
+			want.Paths = stringPointers(ps.Strings())
+		}
+	}
+	return reflect.DeepEqual(want, current)
+}
@@ -0,0 +1,129 @@
+package swarm
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestProjectsService_GetBranch(t *testing.T) {
+	Convey("test ProjectsService_GetBranch", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var patched url.Values
+		mux.HandleFunc("/api/v9/projects/got-dev", handleMembersProject(t, &patched))
+
+		branch, _, err := client.Projects.GetBranch("got-dev", "server")
+		So(err, ShouldBeNil)
+		So(branch.Name, ShouldEqual, "Server")
+		So(branch.Paths, ShouldResemble, []string{"//depot/server/..."})
+
+		_, _, err = client.Projects.GetBranch("got-dev", "unknown")
+		So(errors.Is(err, ErrBranchNotFound), ShouldBeTrue)
+	})
+}
+
+func TestProjectsService_AddBranch(t *testing.T) {
+	Convey("test ProjectsService_AddBranch", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var patched url.Values
+		mux.HandleFunc("/api/v9/projects/got-dev", handleMembersProject(t, &patched))
+
+		_, _, err := client.Projects.AddBranch("got-dev", &BranchOptions{
+			ID:    String("tools"),
+			Name:  String("Tools"),
+			Paths: []*string{String("//depot/tools/...")},
+		})
+		So(err, ShouldBeNil)
+		So(patched.Get("branches[0][id]"), ShouldEqual, "client")
+		So(patched["branches[0][moderators][]"], ShouldResemble, []string{"eyotang"})
+		So(patched.Get("branches[1][id]"), ShouldEqual, "server")
+		So(patched.Get("branches[2][id]"), ShouldEqual, "tools")
+		So(patched["branches[2][paths][]"], ShouldResemble, []string{"//depot/tools/..."})
+
+		_, _, err = client.Projects.AddBranch("got-dev", &BranchOptions{ID: String("client")})
+		So(errors.Is(err, ErrBranchExists), ShouldBeTrue)
+
+		_, _, err = client.Projects.AddBranch("got-dev", &BranchOptions{Name: String("Tools")})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+func TestProjectsService_UpdateBranch(t *testing.T) {
+	Convey("test ProjectsService_UpdateBranch", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var patched url.Values
+		mux.HandleFunc("/api/v9/projects/got-dev", handleMembersProject(t, &patched))
+
+		_, _, err := client.Projects.UpdateBranch("got-dev", "server", &BranchOptions{
+			ID:       String("ignored"),
+			Workflow: String("7"),
+		})
+		So(err, ShouldBeNil)
+		So(patched["branches[0][paths][]"], ShouldResemble, []string{"//depot/client/...", "//depot/common/..."})
+		So(patched.Get("branches[0][workflow]"), ShouldEqual, "6")
+		So(patched.Get("branches[1][id]"), ShouldEqual, "server")
+		So(patched.Get("branches[1][name]"), ShouldEqual, "Server")
+		So(patched["branches[1][paths][]"], ShouldResemble, []string{"//depot/server/..."})
+		So(patched.Get("branches[1][workflow]"), ShouldEqual, "7")
+
+		_, _, err = client.Projects.UpdateBranch("got-dev", "unknown", &BranchOptions{})
+		So(errors.Is(err, ErrBranchNotFound), ShouldBeTrue)
+	})
+}
+
+func TestProjectsService_RemoveBranch(t *testing.T) {
+	Convey("test ProjectsService_RemoveBranch", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var patched url.Values
+		mux.HandleFunc("/api/v9/projects/got-dev", handleMembersProject(t, &patched))
+
+		_, _, err := client.Projects.RemoveBranch("got-dev", "client")
+		So(err, ShouldBeNil)
+		So(patched.Get("branches[0][id]"), ShouldEqual, "server")
+		So(patched.Get("branches[1][id]"), ShouldEqual, "")
+		So(patched["branches[0][moderators][]"], ShouldBeNil)
+	})
+}
+
+func TestProjectsService_UpdateBranchConflict(t *testing.T) {
+	Convey("test ProjectsService_UpdateBranch with a concurrent update of another branch", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		project := &concurrentProject{
+			etags:     true,
+			workflows: map[string]string{"client": "6", "server": "6"},
+			beforePatch: func(p *concurrentProject) {
+				p.workflows["client"] = "8"
+				p.version++
+			},
+		}
+		mux.HandleFunc("/api/v9/projects/got-dev", project.handle(t))
+
+		p, _, err := client.Projects.UpdateBranch("got-dev", "server", &BranchOptions{Workflow: String("7")})
+		So(err, ShouldBeNil)
+		So(p.Branches[0].Workflow, ShouldEqual, "8")
+		So(p.Branches[1].Workflow, ShouldEqual, "7")
+		So(project.workflows, ShouldResemble, map[string]string{"client": "8", "server": "7"})
+		So(project.patches, ShouldEqual, 1)
+	})
+}
+
+func TestUpdateProjectOptions_EmptyLists(t *testing.T) {
+	Convey("test UpdateProjectOptions emptyLists", t, func() {
+		opt := &UpdateProjectOptions{Branches: []*BranchOptions{}, Owners: []*string{}}
+		So(opt.emptyLists(), ShouldResemble, []string{"owners", "branches"})
+		So(string(appendEmptyLists(nil, opt.emptyLists())), ShouldEqual, "owners=&branches=")
+		So(string(appendEmptyLists([]byte("name=a"), []string{"branches"})), ShouldEqual, "name=a&branches=")
+	})
+}
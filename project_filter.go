@@ -0,0 +1,174 @@
+package swarm
+
+import (
+	"fmt"
+	"strings"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+// projectFilterAPIVersions holds the API version with which the Swarm API
+// reference documents each ListProjects filter: the workflow filter is part of
+// the v9 projects API, the other filters were added with v11. ListProjects
+// requests the v9 API, which may ignore the v11 filters.
+var projectFilterAPIVersions = map[string]string{
+	"workflow": "v9",
+	"member":   "v11",
+	"owner":    "v11",
+	"deleted":  "v11",
+	"ids":      "v11",
+}
+
+// UnsupportedFilterError is returned by ListProjects when a filter is not
+// supported by Swarm and cannot be applied to the returned projects either.
+type UnsupportedFilterError struct {
+	Filter     string
+	APIVersion string
+}
+
+func (e *UnsupportedFilterError) Error() string {
+	return fmt.Sprintf("project filter %s is not supported by Swarm: API %s is not available", e.Filter, e.APIVersion)
+}
+
+// projectFilter filters projects locally for the filters of
+// ListProjectsOptions which Swarm may ignore.
+type projectFilter struct {
+	workflow *string
+	member   *string
+	owner    *string
+	deleted  *bool
+	ids      []string
+}
+
+// serverFilters reports whether Swarm applies the filter itself. Only filters
+// of the requested API version are trusted, even if the server supports newer
+// API versions.
+func serverFilters(name string) bool {
+	return projectFilterAPIVersions[name] == defaultAPIVersion
+}
+
+// newProjectFilter returns the filter to apply locally to the result of
+// ListProjects, or nil when all filters are applied by Swarm.
+func newProjectFilter(opt *ListProjectsOptions) *projectFilter {
+	if opt == nil {
+		return nil
+	}
+
+	f := &projectFilter{}
+	empty := true
+	if opt.Workflow != nil && !serverFilters("workflow") {
+		f.workflow, empty = opt.Workflow, false
+	}
+	if opt.Member != nil && !serverFilters("member") {
+		f.member, empty = opt.Member, false
+	}
+	if opt.Owner != nil && !serverFilters("owner") {
+		f.owner, empty = opt.Owner, false
+	}
+	if opt.Deleted != nil && !serverFilters("deleted") {
+		f.deleted, empty = opt.Deleted, false
+	}
+	if len(opt.IDs) > 0 && !serverFilters("ids") {
+		f.ids, empty = opt.IDs, false
+	}
+	if empty {
+		return nil
+	}
+
+	return f
+}
+
+// fields returns the project fields needed to apply the filter.
+func (f *projectFilter) fields() []ProjectField {
+	var fields []ProjectField
+	if f.workflow != nil {
+		fields = append(fields, ProjectFieldWorkflow, ProjectFieldBranches)
+	}
+	if f.member != nil {
+		fields = append(fields, ProjectFieldMembers)
+	}
+	if f.owner != nil {
+		fields = append(fields, ProjectFieldOwners)
+	}
+	if f.deleted != nil {
+		fields = append(fields, ProjectFieldDeleted)
+	}
+	if len(f.ids) > 0 {
+		fields = append(fields, ProjectFieldID)
+	}
+	return fields
+}
+
+// requireFields adds the fields needed by the filter to the fields requested
+// by req, if the fields are limited.
+func (f *projectFilter) requireFields(req *retryablehttp.Request) {
+	q := req.URL.Query()
+	list := q.Get("fields")
+	if list == "" {
+		return
+	}
+
+	names := strings.Split(list, ",")
+	for _, field := range f.fields() {
+		if !containsString(names, field.Name()) {
+			names = append(names, field.Name())
+		}
+	}
+	q.Set("fields", strings.Join(names, ","))
+	req.URL.RawQuery = q.Encode()
+}
+
+// match reports whether p passes the filter.
+func (f *projectFilter) match(p *Project) bool {
+	if f.workflow != nil && !usesWorkflow(p, *f.workflow) {
+		return false
+	}
+	if f.member != nil && !containsString(p.Members, *f.member) {
+		return false
+	}
+	if f.owner != nil && !containsString(p.Owners, *f.owner) {
+		return false
+	}
+	if f.deleted != nil && p.Deleted != *f.deleted {
+		return false
+	}
+	if len(f.ids) > 0 && !containsString(f.ids, p.ID) {
+		return false
+	}
+	return true
+}
+
+// apply returns the projects passing the filter.
+func (f *projectFilter) apply(projects []*Project) []*Project {
+	result := make([]*Project, 0, len(projects))
+	for _, p := range projects {
+		if p != nil && f.match(p) {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// check returns an *UnsupportedFilterError when the filtered projects cannot
+// be trusted. Swarm leaves out deleted projects unless it supports the deleted
+// filter, so an empty list of deleted projects is no answer.
+func (f *projectFilter) check(projects []*Project) error {
+	if f.deleted != nil && *f.deleted && len(projects) == 0 {
+		return &UnsupportedFilterError{Filter: "deleted", APIVersion: projectFilterAPIVersions["deleted"]}
+	}
+	return nil
+}
+
+// usesWorkflow reports whether the project or one of its branches uses the
+// workflow with the given ID.
+func usesWorkflow(p *Project, workflow string) bool {
+	if p.Workflow == workflow {
+		return true
+	}
+	for _, b := range p.Branches {
+		if b.Workflow == workflow {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package swarm
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const filterProjects = `{
+  "projects": [
+	{"id": "a", "workflow": "6", "members": ["eyotang"], "owners": ["root"], "deleted": false, "branches": []},
+	{"id": "b", "workflow": null, "members": ["swarm"], "owners": [], "deleted": false,
+	 "branches": [{"id": "main", "workflow": "6"}]},
+	{"id": "c", "workflow": "5", "members": ["eyotang"], "owners": [], "deleted": true, "branches": []}
+  ]
+}`
+
+func projectIDs(projects []*Project) []string {
+	ids := make([]string, 0, len(projects))
+	for _, p := range projects {
+		ids = append(ids, p.ID)
+	}
+	return ids
+}
+
+func TestProjectsService_ListProjectsFilters(t *testing.T) {
+	Convey("test ListProjects filters", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var query string
+		mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			query = r.URL.RawQuery
+			fmt.Fprint(w, filterProjects)
+		})
+
+		// The workflow filter is part of the default API version and trusted.
+		projects, _, err := client.Projects.ListProjects(&ListProjectsOptions{Workflow: String("6")})
+		So(err, ShouldBeNil)
+		So(query, ShouldEqual, "workflow=6")
+		So(projectIDs(projects), ShouldResemble, []string{"a", "b", "c"})
+
+		projects, _, err = client.Projects.ListProjects(&ListProjectsOptions{Member: String("eyotang"), Deleted: Bool(false)})
+		So(err, ShouldBeNil)
+		So(query, ShouldEqual, "deleted=0&member=eyotang")
+		So(projectIDs(projects), ShouldResemble, []string{"a"})
+
+		projects, _, err = client.Projects.ListProjects(&ListProjectsOptions{Owner: String("root"), IDs: []string{"a", "b"}})
+		So(err, ShouldBeNil)
+		So(query, ShouldEqual, "ids%5B%5D=a&ids%5B%5D=b&owner=root")
+		So(projectIDs(projects), ShouldResemble, []string{"a"})
+
+		projects, _, err = client.Projects.ListProjects(&ListProjectsOptions{Deleted: Bool(true)},
+			WithFields(ProjectFieldName))
+		So(err, ShouldBeNil)
+		So(query, ShouldEqual, "deleted=1&fields=name%2Cdeleted")
+		So(projectIDs(projects), ShouldResemble, []string{"c"})
+	})
+}
+
+func TestProjectsService_ListProjectsServerFilters(t *testing.T) {
+	Convey("test ListProjects filters on a server supporting API v11", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"year": "2022", "version": "SWARM/2022.2/2341817 (2022/09/26)", "apiVersions": [9, 10, 11]}`)
+		})
+		mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+			testParams(t, r, "member=eyotang")
+			fmt.Fprint(w, filterProjects)
+		})
+
+		_, err := client.DiscoverCapabilities()
+		So(err, ShouldBeNil)
+
+		// The v9 API may ignore the v11 filters, so they are still applied.
+		projects, _, err := client.Projects.ListProjects(&ListProjectsOptions{Member: String("eyotang")})
+		So(err, ShouldBeNil)
+		So(projectIDs(projects), ShouldResemble, []string{"a", "c"})
+	})
+}
+
+func TestProjectsService_ListProjectsDeletedUnsupported(t *testing.T) {
+	Convey("test ListProjects listing deleted projects without server support", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+			testParams(t, r, "deleted=1")
+			fmt.Fprint(w, `{"projects": [{"id": "a", "deleted": false}]}`)
+		})
+
+		_, _, err := client.Projects.ListProjects(&ListProjectsOptions{Deleted: Bool(true)})
+		var unsupported *UnsupportedFilterError
+		So(errors.As(err, &unsupported), ShouldBeTrue)
+		So(unsupported.Filter, ShouldEqual, "deleted")
+		So(unsupported.APIVersion, ShouldEqual, "v11")
+	})
+}
+
+func TestProjectsService_ListProjectsFiltersWithFields(t *testing.T) {
+	Convey("test ListProjects filters with a limited field list", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var query string
+		mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			fmt.Fprint(w, `{"projects": [{"name": "A", "members": ["eyotang"]}, {"name": "B", "members": ["swarm"]}]}`)
+		})
+
+		// Fields needed to filter locally are added to the field list.
+		projects, _, err := client.Projects.ListProjects(&ListProjectsOptions{Member: String("eyotang")}, WithFields(ProjectFieldName))
+		So(err, ShouldBeNil)
+		So(query, ShouldEqual, "fields=name%2Cmembers&member=eyotang")
+		So(projects, ShouldHaveLength, 1)
+
+		// Fields are left alone for filters applied by Swarm.
+		projects, _, err = client.Projects.ListProjects(&ListProjectsOptions{Workflow: String("6")}, WithFields(ProjectFieldName))
+		So(err, ShouldBeNil)
+		So(query, ShouldEqual, "fields=name&workflow=6")
+		So(projects, ShouldHaveLength, 2)
+	})
+}
@@ -0,0 +1,370 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/hashicorp/go-retryablehttp"
+	"github.com/pkg/errors"
+)
+
+// maxProjectUpdateAttempts is the number of times a read-modify-write update
+// of a project is attempted before giving up.
+const maxProjectUpdateAttempts = 3
+
+// ErrProjectConflict is returned when a project kept changing while it was
+// updated.
+var ErrProjectConflict = errors.New("project was modified concurrently, giving up")
+
+// modifyProject updates a project using read-modify-write. modify returns the
+// options used to update the project, or nil when no update is needed, and
+// applied reports whether the update is present in a project.
+//
+// When Swarm returns an ETag for the project, the update is sent with
+// If-Match, so a change made by somebody else after the project was read is
+// rejected by the server and the update is retried on top of it. Otherwise
+// the project is read again right before the update, and the update is
+// retried when the project changed in between. After the update the project
+// is read again, and the update is applied again when another update
+// overwrote it.
+func (s *ProjectsService) modifyProject(pid interface{}, modify func(*Project) (*UpdateProjectOptions, error), applied func(*Project) bool, options []RequestOptionFunc) (*Project, *Response, error) {
+	fresh := append(options[:len(options):len(options)], WithoutCache())
+
+	for attempt := 0; attempt < maxProjectUpdateAttempts; attempt++ {
+		before, resp, err := s.GetProject(pid, fresh...)
+		if err != nil {
+			return nil, resp, err
+		}
+
+		opt, err := modify(before)
+		if err != nil {
+			return nil, resp, err
+		}
+		if opt == nil {
+			return before, resp, nil
+		}
+
+		update := withAudit(before, nil, options)
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			update = append(update, withHeader("If-Match", etag))
+		} else {
+			current, resp, err := s.GetProject(pid, fresh...)
+			if err != nil {
+				return nil, resp, err
+			}
+			if !reflect.DeepEqual(before, current) {
+				continue
+			}
+		}
+		updated, resp, err := s.UpdateProject(pid, opt, update...)
+		if resp != nil && resp.StatusCode == http.StatusPreconditionFailed {
+			continue
+		}
+		if err != nil || resp.DryRun != nil {
+			return updated, resp, err
+		}
+
+		// Make sure the update was not overwritten right away.
+		after, resp, err := s.GetProject(pid, fresh...)
+		if err != nil {
+			return nil, resp, err
+		}
+		if reflect.DeepEqual(after, updated) || applied(after) {
+			return after, resp, nil
+		}
+	}
+
+	return nil, nil, ErrProjectConflict
+}
+
+// withHeader sets a header on the request.
+func withHeader(key, value string) RequestOptionFunc {
+	return func(req *retryablehttp.Request) error {
+		req.Header.Set(key, value)
+		return nil
+	}
+}
+
+// AddMembers adds users to the members of a project.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_projects.html
+func (s *ProjectsService) AddMembers(pid interface{}, users []string, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.modifyProject(pid, func(p *Project) (*UpdateProjectOptions, error) {
+		members, changed := addUsers(p.Members, users)
+		if !changed {
+			return nil, nil
+		}
+		return &UpdateProjectOptions{Members: stringPointers(members)}, nil
+	}, func(p *Project) bool {
+		return containsAll(p.Members, users)
+	}, options)
+}
+
+// AddMembersCtx is like AddMembers, but runs the requests with ctx.
+func (s *ProjectsService) AddMembersCtx(ctx context.Context, pid interface{}, users []string, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.AddMembers(pid, users, withContextOption(ctx, options)...)
+}
+
+// RemoveMembers removes users from the members of a project.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_projects.html
+func (s *ProjectsService) RemoveMembers(pid interface{}, users []string, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.modifyProject(pid, func(p *Project) (*UpdateProjectOptions, error) {
+		members, changed := removeUsers(p.Members, users)
+		if !changed {
+			return nil, nil
+		}
+		return &UpdateProjectOptions{Members: stringPointers(members)}, nil
+	}, func(p *Project) bool {
+		return containsNone(p.Members, users)
+	}, options)
+}
+
+// RemoveMembersCtx is like RemoveMembers, but runs the requests with ctx.
+func (s *ProjectsService) RemoveMembersCtx(ctx context.Context, pid interface{}, users []string, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.RemoveMembers(pid, users, withContextOption(ctx, options)...)
+}
+
+// AddOwners adds users to the owners of a project.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_projects.html
+func (s *ProjectsService) AddOwners(pid interface{}, users []string, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.modifyProject(pid, func(p *Project) (*UpdateProjectOptions, error) {
+		owners, changed := addUsers(p.Owners, users)
+		if !changed {
+			return nil, nil
+		}
+		return &UpdateProjectOptions{Owners: stringPointers(owners)}, nil
+	}, func(p *Project) bool {
+		return containsAll(p.Owners, users)
+	}, options)
+}
+
+// AddOwnersCtx is like AddOwners, but runs the requests with ctx.
+func (s *ProjectsService) AddOwnersCtx(ctx context.Context, pid interface{}, users []string, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.AddOwners(pid, users, withContextOption(ctx, options)...)
+}
+
+// RemoveOwners removes users from the owners of a project.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_projects.html
+func (s *ProjectsService) RemoveOwners(pid interface{}, users []string, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.modifyProject(pid, func(p *Project) (*UpdateProjectOptions, error) {
+		owners, changed := removeUsers(p.Owners, users)
+		if !changed {
+			return nil, nil
+		}
+		return &UpdateProjectOptions{Owners: stringPointers(owners)}, nil
+	}, func(p *Project) bool {
+		return containsNone(p.Owners, users)
+	}, options)
+}
+
+// RemoveOwnersCtx is like RemoveOwners, but runs the requests with ctx.
+func (s *ProjectsService) RemoveOwnersCtx(ctx context.Context, pid interface{}, users []string, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.RemoveOwners(pid, users, withContextOption(ctx, options)...)
+}
+
+// AddModerators adds users to the moderators of a single branch of a
+// project. All other branches are sent unchanged.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_projects.html
+func (s *ProjectsService) AddModerators(pid interface{}, branchID string, users []string, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.modifyProject(pid, func(p *Project) (*UpdateProjectOptions, error) {
+		if findBranch(p, branchID) < 0 {
+			return nil, errors.Wrapf(ErrBranchNotFound, "project %q, branch %q", p.ID, branchID)
+		}
+		branches := make([]*BranchOptions, 0, len(p.Branches))
+		changed := false
+		for _, b := range p.Branches {
+			if b.ID == branchID {
+				b.Moderators, changed = addUsers(b.Moderators, users)
+			}
+			branches = append(branches, newBranchOptions(b))
+		}
+		if !changed {
+			return nil, nil
+		}
+		return &UpdateProjectOptions{Branches: branches}, nil
+	}, func(p *Project) bool {
+		i := findBranch(p, branchID)
+		return i >= 0 && containsAll(p.Branches[i].Moderators, users)
+	}, options)
+}
+
+// AddModeratorsCtx is like AddModerators, but runs the requests with ctx.
+func (s *ProjectsService) AddModeratorsCtx(ctx context.Context, pid interface{}, branchID string, users []string, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.AddModerators(pid, branchID, users, withContextOption(ctx, options)...)
+}
+
+// ListFollowers gets the users following a project.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_projects.html
+func (s *ProjectsService) ListFollowers(pid interface{}, options ...RequestOptionFunc) ([]string, *Response, error) {
+	project, err := parseID(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+	u := fmt.Sprintf(apiV10Path+"projects/%s/followers", PathEscape(project))
+
+	req, err := s.client.NewRequest(http.MethodGet, u, nil, withEndpoint(apiV10Path+"projects/{id}/followers", options))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var r *struct {
+		Data *struct {
+			Followers []string `json:"followers"`
+		} `json:"data"`
+	}
+	resp, err := s.client.Do(req, &r)
+	if err != nil {
+		return nil, resp, err
+	}
+	if r.Data == nil {
+		return nil, resp, err
+	}
+
+	return r.Data.Followers, resp, err
+}
+
+// ListFollowersCtx is like ListFollowers, but runs the request with ctx.
+func (s *ProjectsService) ListFollowersCtx(ctx context.Context, pid interface{}, options ...RequestOptionFunc) ([]string, *Response, error) {
+	return s.ListFollowers(pid, withContextOption(ctx, options)...)
+}
+
+// newBranchOptions returns the options needed to send branch b back to Swarm
+// unchanged.
+func newBranchOptions(b Branch) *BranchOptions {
+	opt := &BranchOptions{
+		ID:              String(b.ID),
+		Name:            String(b.Name),
+		Paths:           stringPointers(b.Paths),
+		Defaults:        newDefaultsOptions(b.Defaults.Reviewers),
+		Moderators:      stringPointers(b.Moderators),
+		ModeratorGroups: stringPointers(b.ModeratorGroups),
+	}
+	if b.Workflow != "" {
+		opt.Workflow = String(b.Workflow)
+	}
+	if b.MinimumUpVotes != nil {
+		opt.MinimumUpVotes = Int(*b.MinimumUpVotes)
+	}
+	if b.RetainDefaultReviewers {
+		opt.RetainDefaultReviewers = Bool(true)
+	}
+	return opt
+}
+
+// newDefaultsOptions converts the default reviewers returned by Swarm, e.g.
+// {"users": {"a": {"required": true}, "b": []}, "groups": {"g": {"required": "1"}}},
+// to DefaultsOptions. Groups are prefixed with swarm-group-.
+func newDefaultsOptions(reviewers interface{}) *DefaultsOptions {
+	m, ok := reviewers.(map[string]interface{})
+	if !ok || len(m) == 0 {
+		return nil
+	}
+
+	opt := &DefaultsOptions{Reviewers: make(map[string]*ReviewerOptions)}
+	for kind, prefix := range map[string]string{"users": "", "groups": swarmGroupPrefix} {
+		entries, _ := m[kind].(map[string]interface{})
+		for name, v := range entries {
+			required := "false"
+			if settings, ok := v.(map[string]interface{}); ok {
+				switch r := settings["required"].(type) {
+				case bool:
+					required = fmt.Sprint(r)
+				case string:
+					required = r
+				case float64:
+					required = fmt.Sprint(r)
+				}
+			}
+			opt.Reviewers[addPrefix(name, prefix)] = &ReviewerOptions{Required: String(required)}
+		}
+	}
+	if len(opt.Reviewers) == 0 {
+		return nil
+	}
+
+	return opt
+}
+
+// addUsers adds users to list, it reports whether list was changed.
+func addUsers(list []string, users []string) ([]string, bool) {
+	result := append([]string{}, list...)
+	changed := false
+	for _, user := range users {
+		if !containsString(result, user) {
+			result = append(result, user)
+			changed = true
+		}
+	}
+	return result, changed
+}
+
+// removeUsers removes users from list, it reports whether list was changed.
+func removeUsers(list []string, users []string) ([]string, bool) {
+	result := make([]string, 0, len(list))
+	for _, user := range list {
+		if !containsString(users, user) {
+			result = append(result, user)
+		}
+	}
+	return result, len(result) != len(list)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// containsAll reports whether list contains all of users.
+func containsAll(list []string, users []string) bool {
+	for _, user := range users {
+		if !containsString(list, user) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsNone reports whether list contains none of users.
+func containsNone(list []string, users []string) bool {
+	for _, user := range users {
+		if containsString(list, user) {
+			return false
+		}
+	}
+	return true
+}
+
+// stringValues returns the values of list, nil pointers are skipped.
+func stringValues(list []*string) []string {
+	result := make([]string, 0, len(list))
+	for _, v := range list {
+		if v != nil {
+			result = append(result, *v)
+		}
+	}
+	return result
+}
+
+// stringPointers returns a slice of pointers to the values of list. A nil
+// list results in a nil slice.
+func stringPointers(list []string) []*string {
+	if list == nil {
+		return nil
+	}
+	result := make([]*string, 0, len(list))
+	for _, v := range list {
+		result = append(result, String(v))
+	}
+	return result
+}
@@ -0,0 +1,323 @@
+package swarm
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+const membersProject = `{
+  "project": {
+	"id": "got-dev",
+	"name": "Got-dev",
+	"members": ["eyotang", "swarm"],
+	"owners": ["root"],
+	"branches": [
+	  {
+		"id": "client",
+		"name": "Client",
+		"workflow": "6",
+		"paths": ["//depot/client/...", "//depot/common/..."],
+		"defaults": {
+		  "reviewers": {
+			"users": {"eyotang": {"required": true}, "swarm": []},
+			"groups": {"leads": {"required": "1"}}
+		  }
+		},
+		"moderators": ["eyotang"],
+		"moderators-groups": ["leads"],
+		"minimumUpVotes": 2,
+		"retainDefaultReviewers": true
+	  },
+	  {
+		"id": "server",
+		"name": "Server",
+		"paths": ["//depot/server/..."],
+		"defaults": {"reviewers": []},
+		"moderators": [],
+		"moderators-groups": []
+	  }
+	]
+  }
+}`
+
+// handleMembersProject serves membersProject for GET requests and records the
+// decoded body of PATCH requests in patched.
+func handleMembersProject(t *testing.T, patched *url.Values) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPatch:
+			b, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("Error reading request body: %v", err)
+			}
+			if *patched, err = url.ParseQuery(string(b)); err != nil {
+				t.Errorf("Error parsing request body: %v", err)
+			}
+		default:
+			t.Errorf("Unexpected request method: %s", r.Method)
+		}
+		fmt.Fprint(w, membersProject)
+	}
+}
+
+func TestProjectsService_AddMembers(t *testing.T) {
+	Convey("test ProjectsService_AddMembers", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var patched url.Values
+		mux.HandleFunc("/api/v9/projects/got-dev", handleMembersProject(t, &patched))
+
+		_, _, err := client.Projects.AddMembers("got-dev", []string{"swarm", "tangyq"})
+		So(err, ShouldBeNil)
+		So(patched["members[]"], ShouldResemble, []string{"eyotang", "swarm", "tangyq"})
+		So(patched["owners[]"], ShouldBeNil)
+		So(patched["branches[0][id]"], ShouldBeNil)
+	})
+}
+
+func TestProjectsService_AddMembersUnchanged(t *testing.T) {
+	Convey("test ProjectsService_AddMembers without changes", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var patched url.Values
+		mux.HandleFunc("/api/v9/projects/got-dev", handleMembersProject(t, &patched))
+
+		project, _, err := client.Projects.AddMembers("got-dev", []string{"swarm"})
+		So(err, ShouldBeNil)
+		So(project.Members, ShouldResemble, []string{"eyotang", "swarm"})
+		So(patched, ShouldBeNil)
+	})
+}
+
+func TestProjectsService_RemoveOwners(t *testing.T) {
+	Convey("test ProjectsService_RemoveOwners", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var patched url.Values
+		mux.HandleFunc("/api/v9/projects/got-dev", handleMembersProject(t, &patched))
+
+		_, _, err := client.Projects.RemoveOwners("got-dev", []string{"root"})
+		So(err, ShouldBeNil)
+		So(patched, ShouldResemble, url.Values{"owners": []string{""}})
+	})
+}
+
+func TestProjectsService_AddModerators(t *testing.T) {
+	Convey("test ProjectsService_AddModerators", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		var patched url.Values
+		mux.HandleFunc("/api/v9/projects/got-dev", handleMembersProject(t, &patched))
+
+		_, _, err := client.Projects.AddModerators("got-dev", "client", []string{"tangyq"})
+		So(err, ShouldBeNil)
+		So(patched.Get("branches[0][id]"), ShouldEqual, "client")
+		So(patched["branches[0][paths][]"], ShouldResemble, []string{"//depot/client/...", "//depot/common/..."})
+		So(patched.Get("branches[0][workflow]"), ShouldEqual, "6")
+		So(patched["branches[0][moderators][]"], ShouldResemble, []string{"eyotang", "tangyq"})
+		So(patched["branches[0][moderators-groups][]"], ShouldResemble, []string{"leads"})
+		So(patched.Get("branches[0][defaults][reviewers][eyotang][required]"), ShouldEqual, "true")
+		So(patched.Get("branches[0][defaults][reviewers][swarm][required]"), ShouldEqual, "false")
+		So(patched.Get("branches[0][defaults][reviewers][swarm-group-leads][required]"), ShouldEqual, "1")
+		So(patched.Get("branches[0][minimumUpVotes]"), ShouldEqual, "2")
+		So(patched.Get("branches[0][retainDefaultReviewers]"), ShouldEqual, "1")
+		So(patched.Get("branches[1][id]"), ShouldEqual, "server")
+		So(patched["branches[1][paths][]"], ShouldResemble, []string{"//depot/server/..."})
+		So(patched["branches[1][moderators][]"], ShouldBeNil)
+		So(patched["branches[1][minimumUpVotes]"], ShouldBeNil)
+		So(patched["branches[1][retainDefaultReviewers]"], ShouldBeNil)
+
+		_, _, err = client.Projects.AddModerators("got-dev", "unknown", []string{"tangyq"})
+		So(err, ShouldNotBeNil)
+	})
+}
+
+// concurrentProject serves a project which another admin changes while it is
+// being updated. The project gets a new version on every change, which is
+// sent as ETag when etags is set.
+type concurrentProject struct {
+	etags     bool
+	version   int
+	members   []string
+	workflows map[string]string
+	gets      int
+	patches   int
+	// afterGet runs once, right after the first GET request is handled.
+	// beforePatch and afterPatch run once, right before and right after the
+	// first PATCH request is handled.
+	afterGet    func(p *concurrentProject)
+	beforePatch func(p *concurrentProject)
+	afterPatch  func(p *concurrentProject)
+}
+
+func (p *concurrentProject) write(w http.ResponseWriter) {
+	branches := []map[string]interface{}{}
+	for _, id := range []string{"client", "server"} {
+		branches = append(branches, map[string]interface{}{
+			"id":       id,
+			"name":     id,
+			"paths":    []string{"//depot/" + id + "/..."},
+			"workflow": p.workflows[id],
+		})
+	}
+	if p.etags {
+		w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, p.version))
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"project": map[string]interface{}{"id": "got-dev", "members": p.members, "branches": branches},
+	})
+}
+
+func (p *concurrentProject) handle(t *testing.T) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			p.gets++
+			p.write(w)
+			if fn := p.afterGet; fn != nil {
+				p.afterGet = nil
+				fn(p)
+			}
+			return
+		}
+		testMethod(t, r, http.MethodPatch)
+
+		if fn := p.beforePatch; fn != nil {
+			p.beforePatch = nil
+			fn(p)
+		}
+		if p.etags && r.Header.Get("If-Match") != fmt.Sprintf(`"v%d"`, p.version) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			fmt.Fprint(w, `{"error": "Precondition Failed"}`)
+			return
+		}
+
+		p.patches++
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("Error parsing request body: %v", err)
+		}
+		if members, ok := r.PostForm["members[]"]; ok {
+			p.members = members
+		}
+		for i := 0; r.PostForm.Get(fmt.Sprintf("branches[%d][id]", i)) != ""; i++ {
+			id := r.PostForm.Get(fmt.Sprintf("branches[%d][id]", i))
+			p.workflows[id] = r.PostForm.Get(fmt.Sprintf("branches[%d][workflow]", i))
+		}
+		p.version++
+		p.write(w)
+
+		if fn := p.afterPatch; fn != nil {
+			p.afterPatch = nil
+			fn(p)
+		}
+	}
+}
+
+func TestProjectsService_AddMembersConflict(t *testing.T) {
+	Convey("test ProjectsService_AddMembers with a concurrent update before the PATCH", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		project := &concurrentProject{
+			etags:     true,
+			members:   []string{"eyotang"},
+			workflows: map[string]string{},
+			beforePatch: func(p *concurrentProject) {
+				p.members = append(p.members, "swarm")
+				p.version++
+			},
+		}
+		mux.HandleFunc("/api/v9/projects/got-dev", project.handle(t))
+
+		p, _, err := client.Projects.AddMembers("got-dev", []string{"tangyq"})
+		So(err, ShouldBeNil)
+		So(p.Members, ShouldResemble, []string{"eyotang", "swarm", "tangyq"})
+		So(project.members, ShouldResemble, []string{"eyotang", "swarm", "tangyq"})
+		So(project.patches, ShouldEqual, 1)
+	})
+
+	Convey("test ProjectsService_AddMembers with a concurrent update before the PATCH without ETags", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		project := &concurrentProject{
+			members:   []string{"eyotang"},
+			workflows: map[string]string{},
+			afterGet: func(p *concurrentProject) {
+				p.members = append(p.members, "swarm")
+			},
+		}
+		mux.HandleFunc("/api/v9/projects/got-dev", project.handle(t))
+
+		p, _, err := client.Projects.AddMembers("got-dev", []string{"tangyq"})
+		So(err, ShouldBeNil)
+		So(p.Members, ShouldResemble, []string{"eyotang", "swarm", "tangyq"})
+		So(project.members, ShouldResemble, []string{"eyotang", "swarm", "tangyq"})
+		So(project.patches, ShouldEqual, 1)
+		So(project.gets, ShouldEqual, 5)
+	})
+
+	Convey("test ProjectsService_AddMembers with a concurrent update after the PATCH", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		project := &concurrentProject{
+			members:   []string{"eyotang"},
+			workflows: map[string]string{},
+			afterPatch: func(p *concurrentProject) {
+				p.members = []string{"eyotang", "swarm"}
+			},
+		}
+		mux.HandleFunc("/api/v9/projects/got-dev", project.handle(t))
+
+		p, _, err := client.Projects.AddMembers("got-dev", []string{"tangyq"})
+		So(err, ShouldBeNil)
+		So(p.Members, ShouldResemble, []string{"eyotang", "swarm", "tangyq"})
+		So(project.patches, ShouldEqual, 2)
+	})
+
+	Convey("test ProjectsService_AddMembers giving up", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		patches := 0
+		mux.HandleFunc("/api/v9/projects/got-dev", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("ETag", fmt.Sprintf(`"v%d"`, patches))
+			if r.Method == http.MethodPatch {
+				patches++
+				w.WriteHeader(http.StatusPreconditionFailed)
+			}
+			fmt.Fprint(w, `{"project": {"id": "got-dev", "members": ["eyotang"]}}`)
+		})
+
+		_, _, err := client.Projects.AddMembers("got-dev", []string{"tangyq"})
+		So(err, ShouldEqual, ErrProjectConflict)
+		So(patches, ShouldEqual, maxProjectUpdateAttempts)
+	})
+}
+
+func TestProjectsService_ListFollowers(t *testing.T) {
+	Convey("test ProjectsService_ListFollowers", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v10/projects/got-dev/followers", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprint(w, `{"error": null, "messages": [], "data": {"followers": ["eyotang", "swarm"]}}`)
+		})
+
+		followers, _, err := client.Projects.ListFollowers("got-dev")
+		So(err, ShouldBeNil)
+		So(followers, ShouldResemble, []string{"eyotang", "swarm"})
+	})
+}
@@ -1,6 +1,7 @@
 package swarm
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 )
@@ -13,9 +14,31 @@ type ProjectsService struct {
 	client *Client
 }
 
+// ListProjectsOptions represents the available ListProjects() options. Use
+// FieldList or WithFields to build a validated list of fields.
+//
+// The filters are sent to Swarm. The Member, Owner, Deleted and IDs filters
+// were added with API v11 and may be ignored by the v9 API ListProjects uses,
+// so ListProjects also applies them to the returned projects itself. Listing
+// deleted projects fails with an *UnsupportedFilterError when Swarm returns
+// none, as the v9 API leaves out deleted projects.
 type ListProjectsOptions struct {
-	Fields   *string `query:"fields"`
-	Workflow *string `query:"fields"`
+	Fields *string `url:"fields,omitempty" query:"fields"`
+
+	// Workflow only lists projects using the workflow with this ID, either
+	// on the project or on one of its branches.
+	Workflow *string `url:"workflow,omitempty" query:"workflow"`
+
+	// Member and Owner only list projects the user is a member or owner of.
+	Member *string `url:"member,omitempty" query:"member"`
+	Owner  *string `url:"owner,omitempty" query:"owner"`
+
+	// Deleted only lists deleted projects when true, and only projects which
+	// are not deleted when false.
+	Deleted *bool `url:"deleted,omitempty,int" query:"deleted"`
+
+	// IDs only lists the projects with these IDs.
+	IDs []string `url:"ids[],omitempty" query:"ids"`
 }
 
 // Project represents a project in swarm.
@@ -27,7 +50,10 @@ type Project struct {
 	Name        string   `json:"name"`
 	Description string   `json:"description"`
 	Members     []string `json:"members"`
+	Owners      []string `json:"owners"`
 	Branches    []Branch `json:"branches"`
+	Workflow    string   `json:"workflow"`
+	Deleted     bool     `json:"deleted"`
 }
 
 type Branch struct {
@@ -38,7 +64,10 @@ type Branch struct {
 	Defaults struct {
 		Reviewers interface{} `json:"reviewers"`
 	} `json:"defaults"`
-	Moderators []string `json:"moderators"`
+	Moderators             []string `json:"moderators"`
+	ModeratorGroups        []string `json:"moderators-groups"`
+	MinimumUpVotes         *int     `json:"minimumUpVotes"`
+	RetainDefaultReviewers bool     `json:"retainDefaultReviewers"`
 }
 
 func (p Project) String() string {
@@ -56,6 +85,12 @@ func (s *ProjectsService) ListProjects(opt *ListProjectsOptions, options ...Requ
 		return nil, nil, err
 	}
 
+	// Filter locally when Swarm may ignore some of the filters.
+	filter := newProjectFilter(opt)
+	if filter != nil {
+		filter.requireFields(req)
+	}
+
 	var p *struct {
 		Projects []*Project `json:"projects"`
 	}
@@ -63,10 +98,24 @@ func (s *ProjectsService) ListProjects(opt *ListProjectsOptions, options ...Requ
 	if err != nil {
 		return nil, resp, err
 	}
+	if filter != nil {
+		projects := filter.apply(p.Projects)
+		if err = filter.check(projects); err != nil {
+			return nil, resp, err
+		}
+		return projects, resp, nil
+	}
 
 	return p.Projects, resp, err
 }
 
+// ListProjectsCtx is like ListProjects, but runs the request with ctx.
+func (s *ProjectsService) ListProjectsCtx(ctx context.Context, opt *ListProjectsOptions, options ...RequestOptionFunc) ([]*Project, *Response, error) {
+	return s.ListProjects(opt, withContextOption(ctx, options)...)
+}
+
+// GetProject gets a single project. Use WithFields to limit the returned
+// fields.
 func (s *ProjectsService) GetProject(pid interface{}, options ...RequestOptionFunc) (*Project, *Response, error) {
 	project, err := parseID(pid)
 	if err != nil {
@@ -74,7 +123,7 @@ func (s *ProjectsService) GetProject(pid interface{}, options ...RequestOptionFu
 	}
 	u := fmt.Sprintf("projects/%s", PathEscape(project))
 
-	req, err := s.client.NewRequest(http.MethodGet, u, nil, options)
+	req, err := s.client.NewRequest(http.MethodGet, u, nil, withEndpoint("projects/{id}", options))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -90,9 +139,15 @@ func (s *ProjectsService) GetProject(pid interface{}, options ...RequestOptionFu
 	return p.Project, resp, err
 }
 
+// GetProjectCtx is like GetProject, but runs the request with ctx.
+func (s *ProjectsService) GetProjectCtx(ctx context.Context, pid interface{}, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.GetProject(pid, withContextOption(ctx, options)...)
+}
+
 type CreateProjectOptions struct {
 	Name      *string          `query:"name"`
 	Members   []*string        `query:"members"`
+	Owners    []*string        `query:"owners"`
 	SubGroups []*string        `query:"subgroups"`
 	Branches  []*BranchOptions `query:"branches"`
 }
@@ -101,10 +156,13 @@ type BranchOptions struct {
 	ID              *string          `query:"id"`
 	Name            *string          `query:"name"`
 	Workflow        *string          `query:"workflow"`
-	Paths           *string          `query:"paths"`
+	Paths           []*string        `query:"paths"`
 	Defaults        *DefaultsOptions `query:"defaults"`
 	Moderators      []*string        `query:"moderators"`
 	ModeratorGroups []*string        `query:"moderators-groups"`
+	MinimumUpVotes  *int             `query:"minimumUpVotes"`
+	// RetainDefaultReviewers is only sent when true.
+	RetainDefaultReviewers *bool `query:"retainDefaultReviewers"`
 }
 
 type DefaultsOptions struct {
@@ -116,16 +174,23 @@ type ReviewerOptions struct {
 }
 
 func (s *ProjectsService) CreateProject(opt *CreateProjectOptions, options ...RequestOptionFunc) (*Project, *Response, error) {
+	if opt != nil {
+		if err := validateBranches(opt.Branches); err != nil {
+			return nil, nil, err
+		}
+	}
 	u := "projects"
 
-	req, err := s.client.NewRequest(http.MethodPost, u, opt, options)
+	p := new(struct {
+		*Project `json:"project"`
+	})
+	after := func() interface{} { return p.Project }
+
+	req, err := s.client.NewRequest(http.MethodPost, u, opt, withAudit(nil, after, options))
 	if err != nil {
 		return nil, nil, err
 	}
 
-	p := new(struct {
-		*Project `json:"project"`
-	})
 	resp, err := s.client.Do(req, p)
 	if err != nil {
 		return nil, resp, err
@@ -134,6 +199,11 @@ func (s *ProjectsService) CreateProject(opt *CreateProjectOptions, options ...Re
 	return p.Project, resp, err
 }
 
+// CreateProjectCtx is like CreateProject, but runs the request with ctx.
+func (s *ProjectsService) CreateProjectCtx(ctx context.Context, opt *CreateProjectOptions, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.CreateProject(opt, withContextOption(ctx, options)...)
+}
+
 func (s *ProjectsService) DeleteProject(pid interface{}, options ...RequestOptionFunc) (*Response, error) {
 	project, err := parseID(pid)
 	if err != nil {
@@ -141,7 +211,7 @@ func (s *ProjectsService) DeleteProject(pid interface{}, options ...RequestOptio
 	}
 	u := fmt.Sprintf("projects/%s", PathEscape(project))
 
-	req, err := s.client.NewRequest(http.MethodDelete, u, nil, options)
+	req, err := s.client.NewRequest(http.MethodDelete, u, nil, withEndpoint("projects/{id}", options))
 	if err != nil {
 		return nil, err
 	}
@@ -149,28 +219,75 @@ func (s *ProjectsService) DeleteProject(pid interface{}, options ...RequestOptio
 	return s.client.Do(req, nil)
 }
 
+// DeleteProjectCtx is like DeleteProject, but runs the request with ctx.
+func (s *ProjectsService) DeleteProjectCtx(ctx context.Context, pid interface{}, options ...RequestOptionFunc) (*Response, error) {
+	return s.DeleteProject(pid, withContextOption(ctx, options)...)
+}
+
 type UpdateProjectOptions struct {
 	Name      *string          `query:"name"`
 	Members   []*string        `query:"members"`
+	Owners    []*string        `query:"owners"`
 	SubGroups []*string        `query:"subgroups"`
 	Branches  []*BranchOptions `query:"branches"`
 }
 
+// emptyLists returns the names of the lists which are set, but empty. These
+// are dropped by the encoder, while Swarm needs them to clear the list.
+func (o *UpdateProjectOptions) emptyLists() []string {
+	var names []string
+	if o.Members != nil && len(o.Members) == 0 {
+		names = append(names, "members")
+	}
+	if o.Owners != nil && len(o.Owners) == 0 {
+		names = append(names, "owners")
+	}
+	if o.Branches != nil && len(o.Branches) == 0 {
+		names = append(names, "branches")
+	}
+	return names
+}
+
+// emptyLister is implemented by options with lists which have to be sent to
+// Swarm even when empty, to clear them.
+type emptyLister interface {
+	emptyLists() []string
+}
+
+// appendEmptyLists appends an empty parameter for each of the given names to
+// the encoded body.
+func appendEmptyLists(body []byte, names []string) []byte {
+	for _, name := range names {
+		if len(body) > 0 {
+			body = append(body, '&')
+		}
+		body = append(body, name+"="...)
+	}
+	return body
+}
+
 func (s *ProjectsService) UpdateProject(pid interface{}, opt *UpdateProjectOptions, options ...RequestOptionFunc) (*Project, *Response, error) {
 	project, err := parseID(pid)
 	if err != nil {
 		return nil, nil, err
 	}
+	if opt != nil {
+		if err = validateBranches(opt.Branches); err != nil {
+			return nil, nil, err
+		}
+	}
 	u := fmt.Sprintf("projects/%s", PathEscape(project))
 
-	req, err := s.client.NewRequest(http.MethodPatch, u, opt, options)
+	p := new(struct {
+		Project *Project `json:"project"`
+	})
+	after := func() interface{} { return p.Project }
+
+	req, err := s.client.NewRequest(http.MethodPatch, u, opt, withEndpoint("projects/{id}", withAudit(nil, after, options)))
 	if err != nil {
 		return nil, nil, err
 	}
 
-	p := new(struct {
-		Project *Project `json:"project"`
-	})
 	resp, err := s.client.Do(req, p)
 	if err != nil {
 		return nil, resp, err
@@ -178,3 +295,8 @@ func (s *ProjectsService) UpdateProject(pid interface{}, opt *UpdateProjectOptio
 
 	return p.Project, resp, err
 }
+
+// UpdateProjectCtx is like UpdateProject, but runs the request with ctx.
+func (s *ProjectsService) UpdateProjectCtx(ctx context.Context, pid interface{}, opt *UpdateProjectOptions, options ...RequestOptionFunc) (*Project, *Response, error) {
+	return s.UpdateProject(pid, opt, withContextOption(ctx, options)...)
+}
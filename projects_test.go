@@ -1,6 +1,7 @@
 package swarm
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"testing"
@@ -125,6 +126,7 @@ func TestProjectsService_ListProjects(t *testing.T) {
 				ID:      "main",
 				Name:    "DMXX.YYY",
 				Members: []string{"eyotang", "tangyongqiang", "swarm"},
+				Owners:  []string{"root"},
 				Branches: []Branch{
 					{ID: "artdev", Name: "ArtDev", Workflow: "6", Paths: []string{}},
 					{ID: "hhq", Name: "HHQ", Workflow: "5", Paths: []string{}},
@@ -136,17 +138,20 @@ func TestProjectsService_ListProjects(t *testing.T) {
 		users["tangyongqiang"] = []interface{}{}
 		want[0].Branches[0].Defaults.Reviewers = map[string]interface{}{"users": users}
 		want[0].Branches[0].Moderators = []string{}
+		want[0].Branches[0].ModeratorGroups = []string{}
 
 		users = make(map[string]interface{})
 		users["eyotang"] = map[string]interface{}{"required": true}
 		users["tangyongqiang"] = []interface{}{}
 		want[1].Branches[0].Defaults.Reviewers = map[string]interface{}{"users": users}
 		want[1].Branches[0].Moderators = []string{}
+		want[1].Branches[0].ModeratorGroups = []string{}
 
 		users = make(map[string]interface{})
 		users["tangyongqiang"] = []interface{}{}
 		want[1].Branches[1].Defaults.Reviewers = map[string]interface{}{"users": users}
 		want[1].Branches[1].Moderators = []string{"eyotang", "tangyq"}
+		want[1].Branches[1].ModeratorGroups = []string{}
 
 		So(projects, ShouldResemble, want)
 	})
@@ -220,6 +225,7 @@ func TestProjectsService_GetProject(t *testing.T) {
 		users["tangyongqiang"] = []interface{}{}
 		want.Branches[0].Defaults.Reviewers = map[string]interface{}{"users": users}
 		want.Branches[0].Moderators = []string{}
+		want.Branches[0].ModeratorGroups = []string{}
 
 		So(projects, ShouldResemble, want)
 	})
@@ -280,6 +286,7 @@ func TestProjectsService_CreateProject(t *testing.T) {
 			Name:        "Got-dev",
 			Description: "",
 			Members:     []string{"eyotang", "tangyongqiang"},
+			Owners:      []string{},
 			Branches:    []Branch{},
 		}
 
@@ -361,7 +368,7 @@ func TestProjectsService_CreateProjectWithBranch(t *testing.T) {
 				{
 					Name:     String("Client"),
 					Workflow: String("6"),
-					Paths:    String("//xxx.Mainline/abvc_ArtDev/Assets/...\n//xxx.Mainline/abvc_ArtDev/Assets/Scripts/..."),
+					Paths:    []*string{String("//xxx.Mainline/abvc_ArtDev/Assets/..."), String("//xxx.Mainline/abvc_ArtDev/Assets/Scripts/...")},
 					Defaults: new(DefaultsOptions),
 				},
 			},
@@ -378,6 +385,7 @@ func TestProjectsService_CreateProjectWithBranch(t *testing.T) {
 			Name:        "Got-dev",
 			Description: "",
 			Members:     []string{"eyotang", "tangyongqiang"},
+			Owners:      []string{},
 			Branches: []Branch{
 				{
 					ID:       "client",
@@ -470,7 +478,7 @@ func TestProjectsService_UpdateProject(t *testing.T) {
 				{
 					Name:     String("Client"),
 					Workflow: String("6"),
-					Paths:    String("//xxx.Mainline/abvc_ArtDev/Assets/...\n//xxx.Mainline/abvc_ArtDev/Assets/Scripts/..."),
+					Paths:    []*string{String("//xxx.Mainline/abvc_ArtDev/Assets/..."), String("//xxx.Mainline/abvc_ArtDev/Assets/Scripts/...")},
 					Defaults: new(DefaultsOptions),
 				},
 			},
@@ -487,6 +495,7 @@ func TestProjectsService_UpdateProject(t *testing.T) {
 			Name:        "Got-dev",
 			Description: "",
 			Members:     []string{"eyotang", "tangyongqiang"},
+			Owners:      []string{},
 			Branches: []Branch{
 				{
 					ID:       "client",
@@ -501,6 +510,7 @@ func TestProjectsService_UpdateProject(t *testing.T) {
 		users["tangyongqiang"] = []interface{}{}
 		want.Branches[0].Defaults.Reviewers = map[string]interface{}{"users": users}
 		want.Branches[0].Moderators = []string{}
+		want.Branches[0].ModeratorGroups = []string{}
 
 		So(projects, ShouldResemble, want)
 	})
@@ -565,6 +575,7 @@ func TestProjectsService_UpdateProjectEmptyBranches(t *testing.T) {
 			Name:        "Got-dev",
 			Description: "",
 			Members:     []string{"eyotang", "tangyongqiang"},
+			Owners:      []string{},
 			Branches:    []Branch{},
 		}
 		users := make(map[string]interface{})
@@ -642,7 +653,7 @@ func TestProjectsService_UpdateProjectOnlyModerators(t *testing.T) {
 				{
 					Name:     String("Client"),
 					Workflow: String("6"),
-					Paths:    String("//xxx.Mainline/abvc_ArtDev/Assets/...\n//xxx.Mainline/abvc_ArtDev/Assets/Scripts/..."),
+					Paths:    []*string{String("//xxx.Mainline/abvc_ArtDev/Assets/..."), String("//xxx.Mainline/abvc_ArtDev/Assets/Scripts/...")},
 					Defaults: new(DefaultsOptions),
 				},
 			},
@@ -659,6 +670,7 @@ func TestProjectsService_UpdateProjectOnlyModerators(t *testing.T) {
 			Name:        "Got-dev",
 			Description: "",
 			Members:     []string{"eyotang", "tangyongqiang"},
+			Owners:      []string{},
 			Branches: []Branch{
 				{
 					ID:       "client",
@@ -671,6 +683,7 @@ func TestProjectsService_UpdateProjectOnlyModerators(t *testing.T) {
 
 		want.Branches[0].Defaults.Reviewers = []interface{}{}
 		want.Branches[0].Moderators = []string{"eyotang"}
+		want.Branches[0].ModeratorGroups = []string{}
 
 		So(projects, ShouldResemble, want)
 	})
@@ -700,7 +713,7 @@ func TestProjectsService_UpdateProjectWithUnknownUser(t *testing.T) {
 				{
 					Name:     String("Client"),
 					Workflow: String("6"),
-					Paths:    String("//xxx.Mainline/abvc_ArtDev/Assets/...\n//xxx.Mainline/abvc_ArtDev/Assets/Scripts/..."),
+					Paths:    []*string{String("//xxx.Mainline/abvc_ArtDev/Assets/..."), String("//xxx.Mainline/abvc_ArtDev/Assets/Scripts/...")},
 					Defaults: new(DefaultsOptions),
 				},
 			},
@@ -732,7 +745,7 @@ func TestProjectsService_UpdateProjectReviewer(t *testing.T) {
 					ID:         String("client"),
 					Name:       String("Client"),
 					Workflow:   String("6"),
-					Paths:      String("//Elrond.Mainline/Elrond_ArtDev/Assets/...\n//Elrond.Mainline/Elrond_ArtDev/Assets/Scripts/..."),
+					Paths:      []*string{String("//Elrond.Mainline/Elrond_ArtDev/Assets/..."), String("//Elrond.Mainline/Elrond_ArtDev/Assets/Scripts/...")},
 					Defaults:   new(DefaultsOptions),
 					Moderators: []*string{String("swarm"), String("lejiajun")},
 				},
@@ -768,3 +781,24 @@ func TestProjectsService_UpdateProjectReviewer(t *testing.T) {
 		So(projects, ShouldResemble, want)
 	})
 }
+
+func TestProjectsService_GetProjectCtx(t *testing.T) {
+	Convey("test ProjectsService_GetProjectCtx", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/projects/got-dev", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprint(w, `{"project": {"id": "got-dev", "name": "Got-dev"}}`)
+		})
+
+		project, _, err := client.Projects.GetProjectCtx(context.Background(), "got-dev")
+		So(err, ShouldBeNil)
+		So(project, ShouldResemble, &Project{ID: "got-dev", Name: "Got-dev"})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, _, err = client.Projects.GetProjectCtx(ctx, "got-dev")
+		So(err, ShouldNotBeNil)
+	})
+}
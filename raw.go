@@ -0,0 +1,64 @@
+package swarm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+// Get calls an arbitrary Swarm endpoint with a GET request and decodes the
+// response into out, which may be nil. Paths without an api/ prefix use the
+// default API version, e.g. "projects" becomes "api/v9/projects". params is
+// either a struct with url tags or url.Values and is sent as query string.
+//
+// The request is sent like the requests of the services, so authentication,
+// retries, rate limiting and error checking are applied.
+func (c *Client) Get(ctx context.Context, path string, params interface{}, out interface{}, options ...RequestOptionFunc) (*Response, error) {
+	return c.call(ctx, http.MethodGet, path, params, out, options)
+}
+
+// Post is like Get, but sends a POST request. params is either a struct with
+// query tags or url.Values and is sent form encoded.
+func (c *Client) Post(ctx context.Context, path string, params interface{}, out interface{}, options ...RequestOptionFunc) (*Response, error) {
+	return c.call(ctx, http.MethodPost, path, params, out, options)
+}
+
+// Put is like Post, but sends a PUT request.
+func (c *Client) Put(ctx context.Context, path string, params interface{}, out interface{}, options ...RequestOptionFunc) (*Response, error) {
+	return c.call(ctx, http.MethodPut, path, params, out, options)
+}
+
+// Patch is like Post, but sends a PATCH request.
+func (c *Client) Patch(ctx context.Context, path string, params interface{}, out interface{}, options ...RequestOptionFunc) (*Response, error) {
+	return c.call(ctx, http.MethodPatch, path, params, out, options)
+}
+
+// Delete is like Get, but sends a DELETE request.
+func (c *Client) Delete(ctx context.Context, path string, params interface{}, out interface{}, options ...RequestOptionFunc) (*Response, error) {
+	return c.call(ctx, http.MethodDelete, path, params, out, options)
+}
+
+// Raw calls an arbitrary Swarm endpoint like Get, Post, Put, Patch and Delete
+// do, and returns the undecoded JSON of the response. The JSON is nil when the
+// response has no body, e.g. for 204 No Content.
+func (c *Client) Raw(ctx context.Context, method, path string, params interface{}, options ...RequestOptionFunc) (json.RawMessage, *Response, error) {
+	var body bytes.Buffer
+	resp, err := c.call(ctx, method, path, params, &body, options)
+	if err != nil {
+		return nil, resp, err
+	}
+	if body.Len() == 0 {
+		return nil, resp, nil
+	}
+	return json.RawMessage(body.Bytes()), resp, nil
+}
+
+func (c *Client) call(ctx context.Context, method, path string, params interface{}, out interface{}, options []RequestOptionFunc) (*Response, error) {
+	req, err := c.NewRequest(method, path, params, withContextOption(ctx, options))
+	if err != nil {
+		return nil, err
+	}
+
+	return c.Do(req, out)
+}
@@ -9,14 +9,104 @@ import (
 // RequestOptionFunc can be passed to all API requests to customize the API request.
 type RequestOptionFunc func(*retryablehttp.Request) error
 
+// requestConfig holds per request settings which are used by the client
+// itself instead of being sent to Swarm.
+type requestConfig struct {
+	// noCache bypasses the response cache for the request.
+	noCache bool
+
+	// endpoint is the endpoint template used for instrumentation.
+	endpoint string
+
+	// server is the ID of the Perforce server the request is scoped to. When
+	// nil the default server of the client is used, when empty the request
+	// is not scoped to any server.
+	server *string
+
+	// dryRun overrides the dry-run settings of the client when not nil.
+	dryRun *dryRunConfig
+
+	// auditBefore and auditAfter provide the snapshots of the changed object
+	// recorded in the audit entry of the request.
+	auditBefore interface{}
+	auditAfter  func() interface{}
+
+	// progress is called while reading streamed responses.
+	progress ProgressFunc
+}
+
+// requestConfigKey is the context key used to store the requestConfig.
+type requestConfigKey struct{}
+
+// requestConfigFrom returns the requestConfig stored in ctx, if any.
+func requestConfigFrom(ctx context.Context) requestConfig {
+	cfg, _ := ctx.Value(requestConfigKey{}).(requestConfig)
+	return cfg
+}
+
+// withRequestConfig returns a RequestOptionFunc which updates the
+// requestConfig stored in the context of the request.
+func withRequestConfig(fn func(*requestConfig)) RequestOptionFunc {
+	return func(req *retryablehttp.Request) error {
+		cfg := requestConfigFrom(req.Context())
+		fn(&cfg)
+		*req = *req.WithContext(context.WithValue(req.Context(), requestConfigKey{}, cfg))
+		return nil
+	}
+}
+
 // WithContext runs the request with the provided context
 func WithContext(ctx context.Context) RequestOptionFunc {
 	return func(req *retryablehttp.Request) error {
+		// Keep any settings made by previously applied request options.
+		if cfg, ok := req.Context().Value(requestConfigKey{}).(requestConfig); ok {
+			ctx := context.WithValue(ctx, requestConfigKey{}, cfg)
+			*req = *req.WithContext(ctx)
+			return nil
+		}
 		*req = *req.WithContext(ctx)
 		return nil
 	}
 }
 
+// withContextOption prepends a WithContext option for ctx to options, so
+// that options given by the caller are still applied afterwards.
+func withContextOption(ctx context.Context, options []RequestOptionFunc) []RequestOptionFunc {
+	return append([]RequestOptionFunc{WithContext(ctx)}, options...)
+}
+
+// WithRequestDryRun enables dry-run mode for the request, see WithDryRun. If
+// hook is nil, the hook configured on the client is used.
+func WithRequestDryRun(hook DryRunFunc) RequestOptionFunc {
+	return withRequestConfig(func(cfg *requestConfig) {
+		cfg.dryRun = &dryRunConfig{enabled: true, hook: hook}
+	})
+}
+
+// WithoutDryRun sends the request even if dry-run mode is enabled for the
+// client.
+func WithoutDryRun() RequestOptionFunc {
+	return withRequestConfig(func(cfg *requestConfig) {
+		cfg.dryRun = &dryRunConfig{}
+	})
+}
+
+// WithoutCache bypasses the response cache configured with WithCache, the
+// request is sent without conditional headers and the response is not stored.
+func WithoutCache() RequestOptionFunc {
+	return withRequestConfig(func(cfg *requestConfig) {
+		cfg.noCache = true
+	})
+}
+
+// WithServer scopes the request to the Perforce server with the given ID, for
+// Swarm instances connected to multiple Perforce servers.
+func WithServer(id string) RequestOptionFunc {
+	return withRequestConfig(func(cfg *requestConfig) {
+		cfg.server = &id
+	})
+}
+
 // WithSudo takes either a username or user ID and sets the SUDO request header.
 func WithSudo(uid interface{}) RequestOptionFunc {
 	return func(req *retryablehttp.Request) error {
@@ -0,0 +1,440 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// ReviewsService handles communication with the review related methods of
+// the Swarm API.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_reviews.html
+type ReviewsService struct {
+	client *Client
+}
+
+// Review represents a review in swarm.
+//
+// Swarm API docs:
+// https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_reviews.html
+type Review struct {
+	ID           int              `json:"id"`
+	Author       string           `json:"author"`
+	Type         string           `json:"type"`
+	Description  string           `json:"description"`
+	State        string           `json:"state"`
+	StateLabel   string           `json:"stateLabel"`
+	Pending      bool             `json:"pending"`
+	CommitStatus interface{}      `json:"commitStatus"`
+	TestStatus   string           `json:"testStatus"`
+	DeployStatus string           `json:"deployStatus"`
+	Changes      []int            `json:"changes"`
+	Commits      []int            `json:"commits"`
+	Created      int64            `json:"created"`
+	Updated      int64            `json:"updated"`
+	Versions     []*ReviewVersion `json:"versions"`
+}
+
+// ReviewVersion represents a single version of a review.
+type ReviewVersion struct {
+	// Version is the number of the version, versions are numbered from 1.
+	Version       int    `json:"-"`
+	Change        int    `json:"change"`
+	User          string `json:"user"`
+	Time          int64  `json:"time"`
+	Pending       bool   `json:"pending"`
+	Difference    int    `json:"difference"`
+	AddChangeMode string `json:"addChangeMode"`
+	Stream        string `json:"stream"`
+	ArchiveChange int    `json:"archiveChange"`
+}
+
+func (r Review) String() string {
+	return Stringify(r)
+}
+
+// numberVersions sets the version number of all versions of the review.
+func (r *Review) numberVersions() {
+	for i, v := range r.Versions {
+		if v != nil {
+			v.Version = i + 1
+		}
+	}
+}
+
+// GetReview gets a single review. Use WithFields to limit the returned
+// fields.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_reviews.html
+func (s *ReviewsService) GetReview(review int, options ...RequestOptionFunc) (*Review, *Response, error) {
+	u := fmt.Sprintf("reviews/%d", review)
+
+	req, err := s.client.NewRequest(http.MethodGet, u, nil, withEndpoint("reviews/{id}", options))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var r *struct {
+		Review *Review `json:"review"`
+	}
+	resp, err := s.client.Do(req, &r)
+	if err != nil {
+		return nil, resp, err
+	}
+	if r.Review != nil {
+		r.Review.numberVersions()
+	}
+
+	return r.Review, resp, err
+}
+
+// GetReviewCtx is like GetReview, but runs the request with ctx.
+func (s *ReviewsService) GetReviewCtx(ctx context.Context, review int, options ...RequestOptionFunc) (*Review, *Response, error) {
+	return s.GetReview(review, withContextOption(ctx, options)...)
+}
+
+// ReviewFileOptions represents the available MarkReviewFileRead() and
+// MarkReviewFileUnread() options.
+type ReviewFileOptions struct {
+	Path    *string `query:"path"`
+	Version *int    `query:"version"`
+}
+
+// MarkReviewFileRead marks a file of a review version as read by the
+// authenticated user.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_reviews.html
+func (s *ReviewsService) MarkReviewFileRead(review int, opt *ReviewFileOptions, options ...RequestOptionFunc) (*Response, error) {
+	return s.markReviewFile(review, "read", opt, options)
+}
+
+// MarkReviewFileReadCtx is like MarkReviewFileRead, but runs the request with
+// ctx.
+func (s *ReviewsService) MarkReviewFileReadCtx(ctx context.Context, review int, opt *ReviewFileOptions, options ...RequestOptionFunc) (*Response, error) {
+	return s.MarkReviewFileRead(review, opt, withContextOption(ctx, options)...)
+}
+
+// MarkReviewFileUnread marks a file of a review version as unread by the
+// authenticated user.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_reviews.html
+func (s *ReviewsService) MarkReviewFileUnread(review int, opt *ReviewFileOptions, options ...RequestOptionFunc) (*Response, error) {
+	return s.markReviewFile(review, "unread", opt, options)
+}
+
+// MarkReviewFileUnreadCtx is like MarkReviewFileUnread, but runs the request
+// with ctx.
+func (s *ReviewsService) MarkReviewFileUnreadCtx(ctx context.Context, review int, opt *ReviewFileOptions, options ...RequestOptionFunc) (*Response, error) {
+	return s.MarkReviewFileUnread(review, opt, withContextOption(ctx, options)...)
+}
+
+func (s *ReviewsService) markReviewFile(review int, mark string, opt *ReviewFileOptions, options []RequestOptionFunc) (*Response, error) {
+	u := fmt.Sprintf(apiV10Path+"reviews/%d/files/%s", review, mark)
+
+	req, err := s.client.NewRequest(http.MethodPost, u, opt, withEndpoint(apiV10Path+"reviews/{id}/files/"+mark, options))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(req, nil)
+}
+
+// ListReviewFilesOptions represents the available ListReviewFiles() options.
+// Without From and To the files of the latest version are listed, with only
+// To the files of that version are listed, and with both the files changed
+// between the two versions are listed.
+type ListReviewFilesOptions struct {
+	From *int `url:"from,omitempty"`
+	To   *int `url:"to,omitempty"`
+}
+
+// ReviewFiles represents the files of a review version, or the files changed
+// between two review versions.
+type ReviewFiles struct {
+	// From and To are the review versions the files were listed for, as
+	// given in ListReviewFilesOptions.
+	From int `json:"-"`
+	To   int `json:"-"`
+
+	Root    string        `json:"root"`
+	Limited bool          `json:"limited"`
+	Files   []*ReviewFile `json:"files"`
+}
+
+// ReviewFile represents a single file of a review version.
+type ReviewFile struct {
+	DepotFile string `json:"depotFile"`
+	Action    string `json:"action"`
+	Type      string `json:"type"`
+	Rev       string `json:"rev"`
+	FileSize  string `json:"fileSize"`
+	Digest    string `json:"digest"`
+}
+
+// ListReviewFiles lists the files of a review version, or the files changed
+// between two review versions.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_reviews.html
+func (s *ReviewsService) ListReviewFiles(review int, opt *ListReviewFilesOptions, options ...RequestOptionFunc) (*ReviewFiles, *Response, error) {
+	u := fmt.Sprintf(apiV10Path+"reviews/%d/files", review)
+
+	req, err := s.client.NewRequest(http.MethodGet, u, opt, withEndpoint(apiV10Path+"reviews/{id}/files", options))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var r *struct {
+		Data *ReviewFiles `json:"data"`
+	}
+	resp, err := s.client.Do(req, &r)
+	if err != nil {
+		return nil, resp, err
+	}
+	if r.Data != nil && opt != nil {
+		if opt.From != nil {
+			r.Data.From = *opt.From
+		}
+		if opt.To != nil {
+			r.Data.To = *opt.To
+		}
+	}
+
+	return r.Data, resp, err
+}
+
+// ListReviewFilesCtx is like ListReviewFiles, but runs the request with ctx.
+func (s *ReviewsService) ListReviewFilesCtx(ctx context.Context, review int, opt *ListReviewFilesOptions, options ...RequestOptionFunc) (*ReviewFiles, *Response, error) {
+	return s.ListReviewFiles(review, opt, withContextOption(ctx, options)...)
+}
+
+// GetReviewFileDiffOptions represents the available GetReviewFileDiff()
+// options. From and To are review versions, when From is omitted the diff
+// against the base of the To version is returned.
+type GetReviewFileDiffOptions struct {
+	Path         *string `url:"path,omitempty"`
+	From         *int    `url:"from,omitempty"`
+	To           *int    `url:"to,omitempty"`
+	Lines        *int    `url:"lines,omitempty"`
+	IgnoreWS     *bool   `url:"ignoreWs,omitempty"`
+	MaxDiffLines *int    `url:"maxDiffs,omitempty"`
+}
+
+// ReviewFileDiff represents the diff of a single file between two review
+// versions.
+type ReviewFileDiff struct {
+	// From and To are the review versions the diff was requested for, as
+	// given in GetReviewFileDiffOptions.
+	From int `json:"-"`
+	To   int `json:"-"`
+
+	Path     string      `json:"path"`
+	LeftRev  string      `json:"leftRev"`
+	RightRev string      `json:"rightRev"`
+	IsCut    bool        `json:"isCut"`
+	Summary  DiffSummary `json:"summary"`
+	Lines    []*DiffLine `json:"lines"`
+}
+
+// DiffSummary summarizes the changes of a diff.
+type DiffSummary struct {
+	Adds    int `json:"adds"`
+	Deletes int `json:"deletes"`
+	Updates int `json:"updates"`
+}
+
+// DiffLine represents a single line of a diff. Type is one of add, delete,
+// same or meta.
+type DiffLine struct {
+	Type      string `json:"type"`
+	LeftLine  *int   `json:"leftLine"`
+	RightLine *int   `json:"rightLine"`
+	Value     string `json:"value"`
+}
+
+// GetReviewFileDiff gets the diff of a single file between two review
+// versions.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_reviews.html
+func (s *ReviewsService) GetReviewFileDiff(review int, opt *GetReviewFileDiffOptions, options ...RequestOptionFunc) (*ReviewFileDiff, *Response, error) {
+	u := fmt.Sprintf(apiV10Path+"reviews/%d/files/diff", review)
+
+	req, err := s.client.NewRequest(http.MethodGet, u, opt, withEndpoint(apiV10Path+"reviews/{id}/files/diff", options))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var r *struct {
+		Data *ReviewFileDiff `json:"data"`
+	}
+	resp, err := s.client.Do(req, &r)
+	if err != nil {
+		return nil, resp, err
+	}
+	if r.Data != nil && opt != nil {
+		if opt.From != nil {
+			r.Data.From = *opt.From
+		}
+		if opt.To != nil {
+			r.Data.To = *opt.To
+		}
+	}
+
+	return r.Data, resp, err
+}
+
+// GetReviewFileDiffCtx is like GetReviewFileDiff, but runs the request with
+// ctx.
+func (s *ReviewsService) GetReviewFileDiffCtx(ctx context.Context, review int, opt *GetReviewFileDiffOptions, options ...RequestOptionFunc) (*ReviewFileDiff, *Response, error) {
+	return s.GetReviewFileDiff(review, opt, withContextOption(ctx, options)...)
+}
+
+// List of available modes to add a change to a review.
+const (
+	AddChangeModeAppend  = "append"
+	AddChangeModeReplace = "replace"
+)
+
+// AddReviewChangeOptions represents the available AddReviewChange() options.
+// Mode is either AddChangeModeAppend or AddChangeModeReplace, Swarm replaces
+// the contents of the review when Mode is omitted.
+type AddReviewChangeOptions struct {
+	Change *int    `query:"change"`
+	Mode   *string `query:"mode"`
+}
+
+// AddReviewChange adds a (shelved) changelist to a review, either appending
+// its files to the review or replacing the contents of the review.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_reviews.html
+func (s *ReviewsService) AddReviewChange(review int, opt *AddReviewChangeOptions, options ...RequestOptionFunc) (*Review, *Response, error) {
+	u := fmt.Sprintf("reviews/%d/changes", review)
+
+	req, err := s.client.NewRequest(http.MethodPost, u, opt, withEndpoint("reviews/{id}/changes", options))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r := new(struct {
+		Review *Review `json:"review"`
+	})
+	resp, err := s.client.Do(req, r)
+	if err != nil {
+		return nil, resp, err
+	}
+	if r.Review != nil {
+		r.Review.numberVersions()
+	}
+
+	return r.Review, resp, err
+}
+
+// AddReviewChangeCtx is like AddReviewChange, but runs the request with ctx.
+func (s *ReviewsService) AddReviewChangeCtx(ctx context.Context, review int, opt *AddReviewChangeOptions, options ...RequestOptionFunc) (*Review, *Response, error) {
+	return s.AddReviewChange(review, opt, withContextOption(ctx, options)...)
+}
+
+// ArchiveInactiveReviewsOptions represents the available
+// ArchiveInactiveReviews() options. NotUpdatedSince is a date formatted as
+// YYYY-MM-DD, all reviews not updated since that date are archived.
+type ArchiveInactiveReviewsOptions struct {
+	NotUpdatedSince *string `query:"notUpdatedSince"`
+	Description     *string `query:"description"`
+}
+
+// ArchivedReviews represents the result of archiving inactive reviews.
+type ArchivedReviews struct {
+	ArchivedReviews []*Review     `json:"archivedReviews"`
+	FailedReviews   []interface{} `json:"failedReviews"`
+}
+
+// ArchiveInactiveReviews archives all reviews which have not been updated
+// since the given date.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_reviews.html
+func (s *ReviewsService) ArchiveInactiveReviews(opt *ArchiveInactiveReviewsOptions, options ...RequestOptionFunc) (*ArchivedReviews, *Response, error) {
+	u := "reviews/archive"
+
+	req, err := s.client.NewRequest(http.MethodPost, u, opt, withEndpoint("reviews/archive", options))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	a := new(ArchivedReviews)
+	resp, err := s.client.Do(req, a)
+	if err != nil {
+		return nil, resp, err
+	}
+	for _, r := range a.ArchivedReviews {
+		if r != nil {
+			r.numberVersions()
+		}
+	}
+
+	return a, resp, err
+}
+
+// ArchiveInactiveReviewsCtx is like ArchiveInactiveReviews, but runs the
+// request with ctx.
+func (s *ReviewsService) ArchiveInactiveReviewsCtx(ctx context.Context, opt *ArchiveInactiveReviewsOptions, options ...RequestOptionFunc) (*ArchivedReviews, *Response, error) {
+	return s.ArchiveInactiveReviews(opt, withContextOption(ctx, options)...)
+}
+
+// CleanupReviewOptions represents the available CleanupReview() options.
+// When Reopen is true, files are reopened in the default changelist before
+// the pending changelists are deleted.
+type CleanupReviewOptions struct {
+	Reopen *bool `query:"reopen"`
+}
+
+// CleanupResult represents the result of cleaning up a review.
+type CleanupResult struct {
+	Complete   []interface{} `json:"complete"`
+	Incomplete []interface{} `json:"incomplete"`
+}
+
+// CleanupReview cleans up the pending changelists of a committed review.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_reviews.html
+func (s *ReviewsService) CleanupReview(review int, opt *CleanupReviewOptions, options ...RequestOptionFunc) (*CleanupResult, *Response, error) {
+	u := fmt.Sprintf("reviews/%d/cleanup", review)
+
+	req, err := s.client.NewRequest(http.MethodPost, u, opt, withEndpoint("reviews/{id}/cleanup", options))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c := new(CleanupResult)
+	resp, err := s.client.Do(req, c)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return c, resp, err
+}
+
+// CleanupReviewCtx is like CleanupReview, but runs the request with ctx.
+func (s *ReviewsService) CleanupReviewCtx(ctx context.Context, review int, opt *CleanupReviewOptions, options ...RequestOptionFunc) (*CleanupResult, *Response, error) {
+	return s.CleanupReview(review, opt, withContextOption(ctx, options)...)
+}
+
+// ObliterateReview obliterates a review. This permanently removes the review
+// and all its comments and cannot be undone, it requires admin privileges.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_reviews.html
+func (s *ReviewsService) ObliterateReview(review int, options ...RequestOptionFunc) (*Response, error) {
+	u := fmt.Sprintf(apiV10Path+"reviews/%d/obliterate", review)
+
+	req, err := s.client.NewRequest(http.MethodPost, u, nil, withEndpoint(apiV10Path+"reviews/{id}/obliterate", options))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(req, nil)
+}
+
+// ObliterateReviewCtx is like ObliterateReview, but runs the request with
+// ctx.
+func (s *ReviewsService) ObliterateReviewCtx(ctx context.Context, review int, options ...RequestOptionFunc) (*Response, error) {
+	return s.ObliterateReview(review, withContextOption(ctx, options)...)
+}
@@ -0,0 +1,274 @@
+package swarm
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestReviewsService_GetReview(t *testing.T) {
+	Convey("test ReviewsService_GetReview", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/reviews/12", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprint(w, `{
+			  "review": {
+				"id": 12,
+				"author": "eyotang",
+				"type": "default",
+				"description": "Fix the build",
+				"state": "needsReview",
+				"stateLabel": "Needs Review",
+				"pending": true,
+				"changes": [10, 11, 13],
+				"commits": [],
+				"created": 1654910000,
+				"updated": 1654920000,
+				"versions": [
+				  {"difference": 1, "stream": null, "change": 11, "user": "eyotang", "time": 1654910000, "pending": true, "addChangeMode": "replace", "archiveChange": 11},
+				  {"difference": 1, "stream": null, "change": 13, "user": "eyotang", "time": 1654920000, "pending": true, "addChangeMode": "replace", "archiveChange": 13}
+				]
+			  }
+			}`)
+		})
+
+		review, _, err := client.Reviews.GetReview(12)
+		So(err, ShouldBeNil)
+
+		want := &Review{
+			ID:          12,
+			Author:      "eyotang",
+			Type:        "default",
+			Description: "Fix the build",
+			State:       "needsReview",
+			StateLabel:  "Needs Review",
+			Pending:     true,
+			Changes:     []int{10, 11, 13},
+			Commits:     []int{},
+			Created:     1654910000,
+			Updated:     1654920000,
+			Versions: []*ReviewVersion{
+				{Version: 1, Difference: 1, Change: 11, User: "eyotang", Time: 1654910000, Pending: true, AddChangeMode: "replace", ArchiveChange: 11},
+				{Version: 2, Difference: 1, Change: 13, User: "eyotang", Time: 1654920000, Pending: true, AddChangeMode: "replace", ArchiveChange: 13},
+			},
+		}
+		So(review, ShouldResemble, want)
+	})
+}
+
+func TestReviewsService_MarkReviewFile(t *testing.T) {
+	Convey("test ReviewsService_MarkReviewFile", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v10/reviews/12/files/read", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, "path=%2F%2Fdepot%2Fmain%2Fa.c&version=2")
+			fmt.Fprint(w, `{"error": null, "messages": [], "data": {"path": "//depot/main/a.c", "version": 2}}`)
+		})
+		mux.HandleFunc("/api/v10/reviews/12/files/unread", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, "path=%2F%2Fdepot%2Fmain%2Fa.c&version=2")
+			fmt.Fprint(w, `{"error": null, "messages": [], "data": {"path": "//depot/main/a.c", "version": 2}}`)
+		})
+
+		opt := &ReviewFileOptions{Path: String("//depot/main/a.c"), Version: Int(2)}
+
+		_, err := client.Reviews.MarkReviewFileRead(12, opt)
+		So(err, ShouldBeNil)
+
+		_, err = client.Reviews.MarkReviewFileUnread(12, opt)
+		So(err, ShouldBeNil)
+	})
+}
+
+func TestReviewsService_ListReviewFiles(t *testing.T) {
+	Convey("test ReviewsService_ListReviewFiles", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v10/reviews/12/files", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testParams(t, r, "from=1&to=2")
+			fmt.Fprint(w, `{
+			  "error": null,
+			  "messages": [],
+			  "data": {
+				"root": "//depot/main",
+				"limited": false,
+				"files": [
+				  {"depotFile": "//depot/main/a.c", "action": "edit", "type": "text", "rev": "3", "fileSize": "", "digest": "A4F8D1C5"}
+				]
+			  }
+			}`)
+		})
+
+		files, _, err := client.Reviews.ListReviewFiles(12, &ListReviewFilesOptions{From: Int(1), To: Int(2)})
+		So(err, ShouldBeNil)
+
+		want := &ReviewFiles{
+			From: 1,
+			To:   2,
+			Root: "//depot/main",
+			Files: []*ReviewFile{
+				{DepotFile: "//depot/main/a.c", Action: "edit", Type: "text", Rev: "3", Digest: "A4F8D1C5"},
+			},
+		}
+		So(files, ShouldResemble, want)
+	})
+}
+
+func TestReviewsService_GetReviewFileDiff(t *testing.T) {
+	Convey("test ReviewsService_GetReviewFileDiff", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v10/reviews/12/files/diff", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testParams(t, r, "from=1&path=%2F%2Fdepot%2Fmain%2Fa.c&to=2")
+			fmt.Fprint(w, `{
+			  "error": null,
+			  "messages": [],
+			  "data": {
+				"path": "//depot/main/a.c",
+				"leftRev": "2",
+				"rightRev": "3",
+				"isCut": false,
+				"summary": {"adds": 1, "deletes": 1, "updates": 0},
+				"lines": [
+				  {"type": "meta", "leftLine": null, "rightLine": null, "value": "@@ -1 +1 @@"},
+				  {"type": "delete", "leftLine": 1, "rightLine": null, "value": "-int a;"},
+				  {"type": "add", "leftLine": null, "rightLine": 1, "value": "+int b;"}
+				]
+			  }
+			}`)
+		})
+
+		diff, _, err := client.Reviews.GetReviewFileDiff(12, &GetReviewFileDiffOptions{
+			Path: String("//depot/main/a.c"),
+			From: Int(1),
+			To:   Int(2),
+		})
+		So(err, ShouldBeNil)
+
+		want := &ReviewFileDiff{
+			From:     1,
+			To:       2,
+			Path:     "//depot/main/a.c",
+			LeftRev:  "2",
+			RightRev: "3",
+			Summary:  DiffSummary{Adds: 1, Deletes: 1},
+			Lines: []*DiffLine{
+				{Type: "meta", Value: "@@ -1 +1 @@"},
+				{Type: "delete", LeftLine: Int(1), Value: "-int a;"},
+				{Type: "add", RightLine: Int(1), Value: "+int b;"},
+			},
+		}
+		So(diff, ShouldResemble, want)
+	})
+}
+
+func TestReviewsService_AddReviewChange(t *testing.T) {
+	Convey("test ReviewsService_AddReviewChange", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/reviews/12/changes", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, "change=14&mode=append")
+			fmt.Fprint(w, `{
+			  "review": {
+				"id": 12,
+				"changes": [10, 11, 13, 14],
+				"versions": [
+				  {"change": 11, "addChangeMode": "replace"},
+				  {"change": 13, "addChangeMode": "replace"},
+				  {"change": 15, "addChangeMode": "append"}
+				]
+			  }
+			}`)
+		})
+
+		review, _, err := client.Reviews.AddReviewChange(12, &AddReviewChangeOptions{
+			Change: Int(14),
+			Mode:   String(AddChangeModeAppend),
+		})
+		So(err, ShouldBeNil)
+		So(review.Changes, ShouldResemble, []int{10, 11, 13, 14})
+		So(review.Versions, ShouldHaveLength, 3)
+		So(review.Versions[2], ShouldResemble, &ReviewVersion{Version: 3, Change: 15, AddChangeMode: AddChangeModeAppend})
+	})
+}
+
+func TestReviewsService_ArchiveInactiveReviews(t *testing.T) {
+	Convey("test ReviewsService_ArchiveInactiveReviews", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/reviews/archive", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, "notUpdatedSince=2022-01-01&description=Archived+by+cleanup")
+			fmt.Fprint(w, `{
+			  "archivedReviews": [
+				{"id": 836, "state": "archived", "versions": [{"change": 835}]}
+			  ],
+			  "failedReviews": []
+			}`)
+		})
+
+		archived, _, err := client.Reviews.ArchiveInactiveReviews(&ArchiveInactiveReviewsOptions{
+			NotUpdatedSince: String("2022-01-01"),
+			Description:     String("Archived by cleanup"),
+		})
+		So(err, ShouldBeNil)
+
+		want := &ArchivedReviews{
+			ArchivedReviews: []*Review{
+				{ID: 836, State: "archived", Versions: []*ReviewVersion{{Version: 1, Change: 835}}},
+			},
+			FailedReviews: []interface{}{},
+		}
+		So(archived, ShouldResemble, want)
+	})
+}
+
+func TestReviewsService_CleanupReview(t *testing.T) {
+	Convey("test ReviewsService_CleanupReview", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/reviews/12/cleanup", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			testBody(t, r, "reopen=1")
+			fmt.Fprint(w, `{"complete": [{"1": ["2"]}], "incomplete": []}`)
+		})
+
+		result, _, err := client.Reviews.CleanupReview(12, &CleanupReviewOptions{Reopen: Bool(true)})
+		So(err, ShouldBeNil)
+
+		want := &CleanupResult{
+			Complete:   []interface{}{map[string]interface{}{"1": []interface{}{"2"}}},
+			Incomplete: []interface{}{},
+		}
+		So(result, ShouldResemble, want)
+	})
+}
+
+func TestReviewsService_ObliterateReview(t *testing.T) {
+	Convey("test ReviewsService_ObliterateReview", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v10/reviews/12/obliterate", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPost)
+			fmt.Fprint(w, `{"isValid": true, "message": "review 12 has been Obliterated", "code": 200}`)
+		})
+
+		_, err := client.Reviews.ObliterateReview(12)
+		So(err, ShouldBeNil)
+	})
+}
@@ -0,0 +1,54 @@
+package swarm
+
+import (
+	"context"
+	"net/http"
+)
+
+// ServersService handles communication with the server related methods of
+// the Swarm API, for Swarm instances connected to multiple Perforce servers.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoints.html
+type ServersService struct {
+	client *Client
+}
+
+// Server represents a Perforce server Swarm is connected to.
+type Server struct {
+	ID    string `json:"id"`
+	Label string `json:"label"`
+	Port  string `json:"port"`
+}
+
+func (s Server) String() string {
+	return Stringify(s)
+}
+
+// ListServers gets a list of the Perforce servers Swarm is connected to. The
+// request is never scoped to a server, unless WithServer is given.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoints.html
+func (s *ServersService) ListServers(options ...RequestOptionFunc) ([]*Server, *Response, error) {
+	u := "servers"
+
+	options = append([]RequestOptionFunc{WithServer("")}, options...)
+	req, err := s.client.NewRequest(http.MethodGet, u, nil, withEndpoint("servers", options))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var r *struct {
+		Servers []*Server `json:"servers"`
+	}
+	resp, err := s.client.Do(req, &r)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return r.Servers, resp, err
+}
+
+// ListServersCtx is like ListServers, but runs the request with ctx.
+func (s *ServersService) ListServersCtx(ctx context.Context, options ...RequestOptionFunc) ([]*Server, *Response, error) {
+	return s.ListServers(withContextOption(ctx, options)...)
+}
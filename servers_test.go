@@ -0,0 +1,75 @@
+package swarm
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestServersService_ListServers(t *testing.T) {
+	Convey("test ServersService_ListServers", t, func() {
+		mux, server, _ := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/servers", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprint(w, `{
+			  "servers": [
+				{"id": "chicago", "label": "Chicago", "port": "ssl:chicago:1666"},
+				{"id": "tokyo", "label": "Tokyo", "port": "ssl:tokyo:1666"}
+			  ]
+			}`)
+		})
+
+		client, err := NewBasicAuthClient("username", "password", WithBaseURL(server.URL), WithDefaultServer("chicago"))
+		So(err, ShouldBeNil)
+
+		servers, _, err := client.Servers.ListServers()
+		So(err, ShouldBeNil)
+
+		want := []*Server{
+			{ID: "chicago", Label: "Chicago", Port: "ssl:chicago:1666"},
+			{ID: "tokyo", Label: "Tokyo", Port: "ssl:tokyo:1666"},
+		}
+		So(servers, ShouldResemble, want)
+	})
+}
+
+func TestClient_ServerScope(t *testing.T) {
+	Convey("test Client_ServerScope", t, func() {
+		mux, server, _ := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/chicago/api/v9/projects/got-dev", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprint(w, `{"project": {"id": "got-dev", "name": "Chicago"}}`)
+		})
+		mux.HandleFunc("/tokyo/api/v9/projects/got-dev", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprint(w, `{"project": {"id": "got-dev", "name": "Tokyo"}}`)
+		})
+		mux.HandleFunc("/tokyo/api/v10/reviews/12/files", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testParams(t, r, "to=2")
+			fmt.Fprint(w, `{"data": {"root": "//depot/main", "files": []}}`)
+		})
+
+		client, err := NewBasicAuthClient("username", "password", WithBaseURL(server.URL), WithDefaultServer("chicago"))
+		So(err, ShouldBeNil)
+		So(client.BaseURL().String(), ShouldEqual, server.URL+"/")
+
+		project, _, err := client.Projects.GetProject("got-dev")
+		So(err, ShouldBeNil)
+		So(project.Name, ShouldEqual, "Chicago")
+
+		project, _, err = client.Projects.GetProject("got-dev", WithServer("tokyo"))
+		So(err, ShouldBeNil)
+		So(project.Name, ShouldEqual, "Tokyo")
+
+		files, _, err := client.Reviews.ListReviewFiles(12, &ListReviewFilesOptions{To: Int(2)}, WithServer("tokyo"))
+		So(err, ShouldBeNil)
+		So(files.Root, ShouldEqual, "//depot/main")
+	})
+}
@@ -0,0 +1,89 @@
+package swarm
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+// ProgressFunc is called while a response body is read, with the number of
+// bytes read so far and the total size of the body, or -1 when unknown.
+type ProgressFunc func(read, total int64)
+
+// WithProgress reports the progress of reading the response body of streamed
+// requests and of requests writing the body to an io.Writer.
+func WithProgress(fn ProgressFunc) RequestOptionFunc {
+	return withRequestConfig(func(cfg *requestConfig) {
+		cfg.progress = fn
+	})
+}
+
+// streamTarget is passed to do instead of a value to decode into, to keep
+// the body of the response open.
+type streamTarget struct {
+	body io.ReadCloser
+}
+
+// DoStream sends an API request and returns the body of the response without
+// reading it, once the status of the response has been checked. The caller
+// has to close the returned body. Streamed responses are never cached.
+func (c *Client) DoStream(req *retryablehttp.Request) (io.ReadCloser, *Response, error) {
+	if err := WithoutCache()(req); err != nil {
+		return nil, nil, err
+	}
+
+	stream := &streamTarget{}
+	resp, err := c.Do(req, stream)
+	if err != nil {
+		if stream.body != nil {
+			stream.body.Close()
+		}
+		return nil, resp, err
+	}
+	if stream.body == nil {
+		// E.g. in dry-run mode, no response body was received.
+		return http.NoBody, resp, nil
+	}
+
+	return stream.body, resp, nil
+}
+
+// Stream calls an arbitrary Swarm endpoint with a GET request like Get does,
+// and returns the body of the response without reading it, e.g. to export
+// large lists. The caller has to close the returned body.
+func (c *Client) Stream(ctx context.Context, path string, params interface{}, options ...RequestOptionFunc) (io.ReadCloser, *Response, error) {
+	req, err := c.NewRequest(http.MethodGet, path, params, withContextOption(ctx, options))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c.DoStream(req)
+}
+
+// progressReader reports the progress of reading from an io.ReadCloser.
+type progressReader struct {
+	io.ReadCloser
+	read     int64
+	total    int64
+	progress ProgressFunc
+}
+
+// newProgressReader returns body, reporting the progress of reading it to
+// progress if not nil.
+func newProgressReader(body io.ReadCloser, total int64, progress ProgressFunc) io.ReadCloser {
+	if progress == nil {
+		return body
+	}
+	return &progressReader{ReadCloser: body, total: total, progress: progress}
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.read += int64(n)
+		r.progress(r.read, r.total)
+	}
+	return n, err
+}
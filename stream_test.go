@@ -0,0 +1,92 @@
+package swarm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClient_Stream(t *testing.T) {
+	Convey("test Client Stream", t, func() {
+		mux, server, client := setup(t, WithCache(NewMemoryCache(time.Minute)))
+		defer teardown(server)
+
+		export := `{"projects": [` + strings.Repeat(`{"id": "p"},`, 1000) + `{"id": "last"}]}`
+		mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testParams(t, r, "fields=id")
+			testHeader(t, r, "If-None-Match", "")
+			w.Header().Set("ETag", `"v1"`)
+			w.Header().Set("Content-Length", fmt.Sprint(len(export)))
+			fmt.Fprint(w, export)
+		})
+
+		var calls int
+		var read, total int64
+		body, resp, err := client.Stream(context.Background(), "projects",
+			&ListProjectsOptions{Fields: String("id")},
+			WithProgress(func(r, t int64) { calls, read, total = calls+1, r, t }))
+		So(err, ShouldBeNil)
+		So(resp.StatusCode, ShouldEqual, http.StatusOK)
+
+		b, err := ioutil.ReadAll(body)
+		So(err, ShouldBeNil)
+		So(body.Close(), ShouldBeNil)
+		So(string(b), ShouldEqual, export)
+		So(calls, ShouldBeGreaterThan, 0)
+		So(read, ShouldEqual, len(export))
+		So(total, ShouldEqual, len(export))
+
+		// Streamed responses are not cached, so no conditional headers are sent.
+		body, _, err = client.Stream(context.Background(), "projects", &ListProjectsOptions{Fields: String("id")})
+		So(err, ShouldBeNil)
+		So(body.Close(), ShouldBeNil)
+	})
+}
+
+func TestClient_StreamError(t *testing.T) {
+	Convey("test Client Stream with an error response", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"error": "Forbidden"}`)
+		})
+
+		body, resp, err := client.Stream(context.Background(), "projects", nil)
+		So(err, ShouldNotBeNil)
+		So(body, ShouldBeNil)
+		So(resp.StatusCode, ShouldEqual, http.StatusForbidden)
+	})
+}
+
+func TestDo_WriterProgress(t *testing.T) {
+	Convey("test Do writing to an io.Writer with progress", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/activity", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"activity": []}`)
+		})
+
+		var read int64
+		req, err := client.NewRequest(http.MethodGet, "activity", nil, []RequestOptionFunc{
+			WithProgress(func(r, _ int64) { read = r }),
+		})
+		So(err, ShouldBeNil)
+
+		var buf bytes.Buffer
+		_, err = client.Do(req, &buf)
+		So(err, ShouldBeNil)
+		So(buf.String(), ShouldEqual, `{"activity": []}`)
+		So(read, ShouldEqual, buf.Len())
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"math/rand"
 	"net/http"
 	"net/url"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -20,18 +21,22 @@ import (
 	retryablehttp "github.com/hashicorp/go-retryablehttp"
 	"github.com/hetiansu5/urlquery"
 	"github.com/pkg/errors"
-	"golang.org/x/time/rate"
 )
 
 const (
 	defaultBaseURL = "https://myswarm.url/"
+	apiPrefix      = "api/"
 	apiV9Path      = "api/v9/"
 	apiV10Path     = "api/v10/"
+	apiV11Path     = "api/v11/"
 
 	headerRateLimit = "RateLimit-Limit"
 	headerRateReset = "RateLimit-Reset"
 )
 
+// apiVersionPathRegexp matches a versioned API path at the end of a base URL.
+var apiVersionPathRegexp = regexp.MustCompile(`api/v[0-9]+/$`)
+
 // AuthType represents an authentication type within Swarm.
 //
 // Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoints.html
@@ -62,14 +67,37 @@ type Client struct {
 	// Limiter is used to limit API calls and prevent 429 responses.
 	limiter RateLimiter
 
+	// cache is used to cache and revalidate GET responses.
+	cache CacheStore
+
+	// instrumenter is used to instrument all API calls.
+	instrumenter Instrumenter
+
+	// customRequestLogHook is the request log hook configured by the user.
+	customRequestLogHook retryablehttp.RequestLogHook
+
+	// customResponseLogHook is the response log hook configured by the user.
+	customResponseLogHook retryablehttp.ResponseLogHook
+
+	// customCheckRetry is the retry policy configured by the user.
+	customCheckRetry retryablehttp.CheckRetry
+
+	// breaker is used to fail fast while the Swarm server is unavailable.
+	breaker *circuitBreaker
+
 	// Token type used to make authenticated API calls.
 	authType AuthType
 
-	// Base URL for API requests. Defaults to the public Swarm API, but can be
+	// Root URL of the Swarm server. Defaults to the public Swarm API, but can be
 	// set to a domain endpoint to use with a self hosted Swarm server. baseURL
-	// should always be specified with a trailing slash.
+	// is always stored with a trailing slash and without an API version path,
+	// and it is never modified after the client has been created.
 	baseURL *url.URL
 
+	// server is the ID of the Perforce server all requests are scoped to, if
+	// Swarm is connected to multiple Perforce servers.
+	server string
+
 	// Username and password used for basic authentication.
 	username, password string
 
@@ -79,9 +107,32 @@ type Client struct {
 	// Protects the token field from concurrent read/write accesses.
 	tokenLock sync.RWMutex
 
+	// probeCapabilities is used to discover the capabilities of the server
+	// when the client is created.
+	probeCapabilities bool
+
+	// decodeConfig holds the settings used to decode responses.
+	decodeConfig decodeConfig
+
+	// auditSink receives an entry for every mutating request.
+	auditSink AuditSink
+
+	// dryRunConfig makes the client return mutating requests instead of
+	// sending them.
+	dryRunConfig dryRunConfig
+
+	// capabilities holds the discovered capabilities of the server.
+	capabilities *Capabilities
+
+	// Protects the capabilities field from concurrent read/write accesses.
+	capabilitiesLock sync.RWMutex
+
 	// Services used for talking to different parts of the Swarm API.
+	Files     *FilesService
 	Workflows *WorkflowService
 	Projects  *ProjectsService
+	Reviews   *ReviewsService
+	Servers   *ServersService
 }
 
 // PageInfo Paging common input parameter structure
@@ -107,6 +158,12 @@ func NewBasicAuthClient(username, password string, options ...ClientOptionFunc)
 	client.username = username
 	client.password = password
 
+	if client.probeCapabilities {
+		if _, err := client.DiscoverCapabilities(); err != nil {
+			return nil, err
+		}
+	}
+
 	return client, nil
 }
 
@@ -116,13 +173,15 @@ func newClient(options ...ClientOptionFunc) (*Client, error) {
 	// Configure the HTTP client.
 	c.client = &retryablehttp.Client{
 		Backoff:      c.retryHTTPBackoff,
-		CheckRetry:   c.retryHTTPCheck,
+		CheckRetry:   c.checkRetry,
 		ErrorHandler: retryablehttp.PassthroughErrorHandler,
 		HTTPClient:   cleanhttp.DefaultPooledClient(),
 		RetryWaitMin: 100 * time.Millisecond,
 		RetryWaitMax: 400 * time.Millisecond,
 		RetryMax:     5,
 	}
+	c.client.RequestLogHook = c.requestLogHook
+	c.client.ResponseLogHook = c.responseLogHook
 
 	// Set the default base URL.
 	c.setBaseURL(defaultBaseURL)
@@ -138,7 +197,10 @@ func newClient(options ...ClientOptionFunc) (*Client, error) {
 	}
 
 	// Create all the public services.
+	c.Files = &FilesService{client: c}
 	c.Projects = &ProjectsService{client: c}
+	c.Reviews = &ReviewsService{client: c}
+	c.Servers = &ServersService{client: c}
 	c.Workflows = &WorkflowService{client: c}
 	return c, nil
 }
@@ -174,8 +236,8 @@ func (c *Client) retryHTTPBackoff(min, max time.Duration, attemptNum int, resp *
 }
 
 // rateLimitBackoff provides a callback for Client.Backoff which will use the
-// RateLimit-Reset header to determine the time to wait. We add some jitter
-// to prevent a thundering herd.
+// RateLimit-Reset or Retry-After header to determine the time to wait. We add
+// some jitter to prevent a thundering herd.
 //
 // min and max are mainly used for bounding the jitter that will be added to
 // the reset time retrieved from the headers. But if the final wait time is
@@ -195,55 +257,19 @@ func rateLimitBackoff(min, max time.Duration, attemptNum int, resp *http.Respons
 					min = wait
 				}
 			}
+		} else if v := resp.Header.Get(headerRetryAfter); v != "" {
+			// Only update min if the given time to wait is longer.
+			if wait := time.Until(parseRetryAfter(v, time.Now())); wait > min {
+				min = wait
+			}
 		}
 	}
 
 	return min + jitter
 }
 
-// configureLimiter configures the rate limiter.
-func (c *Client) configureLimiter(ctx context.Context) error {
-	// Set default values for when rate limiting is disabled.
-	limit := rate.Inf
-	burst := 0
-
-	defer func() {
-		// Create a new limiter using the calculated values.
-		c.limiter = rate.NewLimiter(limit, burst)
-	}()
-
-	// Create a new request.
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL.String(), nil)
-	if err != nil {
-		return err
-	}
-
-	// Make a single request to retrieve the rate limit headers.
-	resp, err := c.client.HTTPClient.Do(req)
-	if err != nil {
-		return err
-	}
-	resp.Body.Close()
-
-	if v := resp.Header.Get(headerRateLimit); v != "" {
-		if rateLimit, _ := strconv.ParseFloat(v, 64); rateLimit > 0 {
-			// The rate limit is based on requests per minute, so for our limiter to
-			// work correctly we divide the limit by 60 to get the limit per second.
-			rateLimit /= 60
-			// Configure the limit and burst using a split of 2/3 for the limit and
-			// 1/3 for the burst. This enables clients to burst 1/3 of the allowed
-			// calls before the limiter kicks in. The remaining calls will then be
-			// spread out evenly using intervals of time.Second / limit which should
-			// prevent hitting the rate limit.
-			limit = rate.Limit(rateLimit * 0.66)
-			burst = int(rateLimit * 0.33)
-		}
-	}
-
-	return nil
-}
-
-// BaseURL return a copy of the baseURL.
+// BaseURL return a copy of the baseURL, which is the root URL of the Swarm
+// server without any API version path.
 func (c *Client) BaseURL() *url.URL {
 	u := *c.baseURL
 	return &u
@@ -261,9 +287,9 @@ func (c *Client) setBaseURL(urlStr string) error {
 		return err
 	}
 
-	if !strings.HasSuffix(baseURL.Path, apiV9Path) {
-		baseURL.Path += apiV9Path
-	}
+	// Strip any API version path, the version is added per request.
+	baseURL.Path = apiVersionPathRegexp.ReplaceAllString(baseURL.Path, "")
+	baseURL.RawPath = ""
 
 	// Update the base URL of the client.
 	c.baseURL = baseURL
@@ -271,24 +297,48 @@ func (c *Client) setBaseURL(urlStr string) error {
 	return nil
 }
 
+// requestURL builds the URL for the given relative API path. Paths that
+// already start with an API prefix (e.g. api/v10/) are resolved against the
+// root URL as is, all other paths are resolved against the v9 API. When a
+// server is given, the path is scoped to that Perforce server. A new URL is
+// returned for every call, so the client itself is never modified.
+func (c *Client) requestURL(server, path string) (*url.URL, error) {
+	unescaped, err := url.PathUnescape(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(path, apiPrefix) {
+		path = apiV9Path + path
+		unescaped = apiV9Path + unescaped
+	}
+
+	if server != "" {
+		path = PathEscape(server) + "/" + path
+		unescaped = server + "/" + unescaped
+	}
+
+	u := *c.baseURL
+	u.RawPath = c.baseURL.Path + path
+	u.Path = c.baseURL.Path + unescaped
+
+	return &u, nil
+}
+
 // NewRequest creates a new API request. The method expects a relative URL
 // path that will be resolved relative to the base URL of the Client.
 // Relative URL paths should always be specified without a preceding slash.
 // If specified, the value pointed to by body is JSON encoded and included
 // as the request body.
 func (c *Client) NewRequest(method, path string, opt interface{}, options []RequestOptionFunc) (*retryablehttp.Request, error) {
-	u := *c.baseURL
-	unescaped, err := url.PathUnescape(path)
-	if err != nil {
+	if err := c.checkSupported(path); err != nil {
 		return nil, err
 	}
 
-	// Set the encoded path data
-	if strings.HasPrefix(path, apiV10Path) {
-		c.baseURL.Path = strings.TrimSuffix(c.baseURL.Path, apiV9Path)
+	u, err := c.requestURL(c.server, path)
+	if err != nil {
+		return nil, err
 	}
-	u.RawPath = c.baseURL.Path + path
-	u.Path = c.baseURL.Path + unescaped
 
 	// Create a request specific headers map.
 	reqHeaders := make(http.Header)
@@ -298,24 +348,28 @@ func (c *Client) NewRequest(method, path string, opt interface{}, options []Requ
 	switch {
 	case method == http.MethodPost || method == http.MethodPut || method == http.MethodPatch:
 		reqHeaders.Set("Content-Type", "application/x-www-form-urlencoded")
-		if opt != nil {
+		if values, ok := opt.(url.Values); ok {
+			body = []byte(values.Encode())
+		} else if opt != nil {
 			//encoder := urlquery.NewEncoder(urlquery.WithNeedEmptyValue(true))
 			encoder := urlquery.NewEncoder()
 			if body, err = encoder.Marshal(opt); err != nil {
 				return nil, err
 			}
-			// 特殊处理branches逻辑
-			if projectOpts, assetOpts := opt.(*UpdateProjectOptions); assetOpts {
-				if len(projectOpts.Branches) == 0 && projectOpts.Branches != nil {
-					if byteBody, assetByte := body.([]byte); assetByte {
-						body = []byte(string(byteBody) + "&branches=")
-					}
+			// 特殊处理空列表(branches, members, exclusions等)逻辑
+			if lister, ok := opt.(emptyLister); ok {
+				if byteBody, assetByte := body.([]byte); assetByte {
+					body = appendEmptyLists(byteBody, lister.emptyLists())
 				}
 			}
 			//fmt.Println(string(body.([]byte)))
 		}
 
 	case opt != nil:
+		if values, ok := opt.(url.Values); ok {
+			u.RawQuery = values.Encode()
+			break
+		}
 		q, err := query.Values(opt)
 		if err != nil {
 			return nil, err
@@ -337,6 +391,16 @@ func (c *Client) NewRequest(method, path string, opt interface{}, options []Requ
 		}
 	}
 
+	// Scope the request to another server when requested.
+	if server := requestConfigFrom(req.Context()).server; server != nil && *server != c.server {
+		su, err := c.requestURL(*server, path)
+		if err != nil {
+			return nil, err
+		}
+		su.RawQuery = req.URL.RawQuery
+		req.URL = su
+	}
+
 	// Set the request specific headers.
 	for k, v := range reqHeaders {
 		req.Header[k] = v
@@ -350,6 +414,22 @@ func (c *Client) NewRequest(method, path string, opt interface{}, options []Requ
 // pagination links.
 type Response struct {
 	*http.Response
+
+	// FromCache is true when Swarm responded with 304 Not Modified and the
+	// body was served from the response cache.
+	FromCache bool
+
+	// UnknownFields holds the paths of the fields in the response which were
+	// not decoded, when strict decoding is enabled.
+	UnknownFields []string
+
+	// Raw holds the original JSON of the response, when raw capture is
+	// enabled.
+	Raw json.RawMessage
+
+	// DryRun holds the request which would have been sent, when the request
+	// was not sent because dry-run mode is enabled.
+	DryRun *DryRunRequest
 }
 
 // newResponse creates a new Response for the provided http.Response.
@@ -364,12 +444,46 @@ func newResponse(r *http.Response) *Response {
 // interface, the raw response body will be written to v, without attempting to
 // first decode it.
 func (c *Client) Do(req *retryablehttp.Request, v interface{}) (*Response, error) {
-	// If not yet configured, try to configure the rate limiter. Fail
-	// silently as the limiter will be disabled in case of an error.
-	c.configureLimiterOnce.Do(func() { c.configureLimiter(req.Context()) })
+	if c.auditSink != nil && mutating(req) {
+		return c.audit(req, v)
+	}
+	return c.dispatch(req, v)
+}
+
+// dispatch sends the request, instrumenting it if an Instrumenter is set.
+func (c *Client) dispatch(req *retryablehttp.Request, v interface{}) (*Response, error) {
+	if c.instrumenter != nil {
+		return c.instrument(req, v)
+	}
+	return c.do(req, v)
+}
+
+func (c *Client) do(req *retryablehttp.Request, v interface{}) (*Response, error) {
+	// Return mutating requests instead of sending them in dry-run mode.
+	if dryRun := c.dryRun(req); dryRun.enabled && mutating(req) {
+		return dryRunResponse(req, dryRun.hook)
+	}
+
+	// Fail fast when the circuit for the host is open. Requests which are
+	// already guarded (e.g. when retried after a 401) are not checked again.
+	if _, guarded := req.Context().Value(circuitHostKey{}).(string); c.breaker != nil && !guarded {
+		host := req.URL.Host
+		probe, err := c.breaker.allow(host)
+		if err != nil {
+			return nil, err
+		}
+		if probe {
+			defer c.breaker.done(host)
+		}
+		req = req.WithContext(context.WithValue(req.Context(), circuitHostKey{}, host))
+	}
 
 	// Wait will block until the limiter can obtain a new token.
-	err := c.limiter.Wait(req.Context())
+	waitStart := time.Now()
+	err := c.rateLimiter().Wait(req.Context())
+	if info, ok := req.Context().Value(requestInfoKey{}).(*RequestInfo); ok {
+		info.LimiterWait += time.Since(waitStart)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -404,6 +518,16 @@ func (c *Client) Do(req *retryablehttp.Request, v interface{}) (*Response, error
 		}
 	}
 
+	// Add the conditional headers when the response may be cached.
+	var (
+		key    string
+		cached *CacheEntry
+	)
+	if c.cache != nil && req.Method == http.MethodGet && !requestConfigFrom(req.Context()).noCache {
+		key = cacheKey(req.Request)
+		cached = c.prepareCachedRequest(key, req.Request)
+	}
+
 	resp, err := c.client.Do(req)
 	if err != nil {
 		return nil, err
@@ -415,12 +539,28 @@ func (c *Client) Do(req *retryablehttp.Request, v interface{}) (*Response, error
 		if _, err := c.generateBasicToken(req.Context(), basicAuthToken); err != nil {
 			return nil, err
 		}
-		return c.Do(req, v)
+		return c.do(req, v)
 	}
-	defer resp.Body.Close()
+
+	// The body of streamed responses is closed by the caller.
+	stream, streaming := v.(*streamTarget)
+	defer func() {
+		if stream == nil || stream.body == nil {
+			resp.Body.Close()
+		}
+	}()
 
 	response := newResponse(resp)
 
+	if key != "" {
+		if response.FromCache, err = c.cacheResponse(key, cached, resp); err != nil {
+			return response, err
+		}
+	}
+	if resp.StatusCode == http.StatusNotModified && !response.FromCache {
+		return response, ErrNotModified
+	}
+
 	err = CheckResponse(resp)
 	if err != nil {
 		// Even though there was an error, we still return the response
@@ -428,12 +568,14 @@ func (c *Client) Do(req *retryablehttp.Request, v interface{}) (*Response, error
 		return response, err
 	}
 
-	if v != nil {
-		if w, ok := v.(io.Writer); ok {
-			_, err = io.Copy(w, resp.Body)
-		} else {
-			err = json.NewDecoder(resp.Body).Decode(v)
-		}
+	progress := requestConfigFrom(req.Context()).progress
+	switch w, ok := v.(io.Writer); {
+	case streaming:
+		stream.body = newProgressReader(resp.Body, resp.ContentLength, progress)
+	case ok:
+		_, err = io.Copy(w, newProgressReader(resp.Body, resp.ContentLength, progress))
+	default:
+		err = c.decode(response, resp.Body, v)
 	}
 
 	return response, err
@@ -3,12 +3,14 @@ package swarm
 import (
 	"bytes"
 	"context"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/hashicorp/go-retryablehttp"
@@ -21,21 +23,27 @@ var timeLayout = "2006-01-02T15:04:05Z07:00"
 // setup sets up a test HTTP server along with a gitlab.Client that is
 // configured to talk to that test server.  Tests should register handlers on
 // mux which provide mock responses for the API method being tested.
-func setup(t *testing.T) (*http.ServeMux, *httptest.Server, *Client) {
+func setup(t *testing.T, options ...ClientOptionFunc) (*http.ServeMux, *httptest.Server, *Client) {
 	// mux is the HTTP request multiplexer used with the test server.
 	mux := http.NewServeMux()
 
 	// server is a test HTTP server used to provide mock API responses.
 	server := httptest.NewServer(mux)
 	username := os.Getenv("USERNAME")
+	if len(username) == 0 {
+		username = "username"
+	}
 	password := os.Getenv("PASSWORD")
+	if len(password) == 0 {
+		password = "password"
+	}
 	url := os.Getenv("URL")
 	if len(url) > 0 {
 		server.URL = url
 	}
 
 	// client is the Gitlab client being tested.
-	client, err := NewBasicAuthClient(username, password, WithBaseURL(server.URL))
+	client, err := NewBasicAuthClient(username, password, append([]ClientOptionFunc{WithBaseURL(server.URL)}, options...)...)
 	if err != nil {
 		server.Close()
 		t.Fatalf("Failed to create client: %v", err)
@@ -61,6 +69,12 @@ func testMethod(t *testing.T, r *http.Request, want string) {
 	}
 }
 
+func testHeader(t *testing.T, r *http.Request, header, want string) {
+	if got := r.Header.Get(header); got != want {
+		t.Errorf("Header.Get(%q) returned %q, want %q", header, got, want)
+	}
+}
+
 func testBody(t *testing.T, r *http.Request, want string) {
 	buffer := new(bytes.Buffer)
 	_, err := buffer.ReadFrom(r.Body)
@@ -99,7 +113,7 @@ func TestNewBasicAuthClient(t *testing.T) {
 	Convey("test NewBasicAuthClient", t, func() {
 		var (
 			c, err          = NewBasicAuthClient("", "")
-			expectedBaseURL = defaultBaseURL + apiV9Path
+			expectedBaseURL = defaultBaseURL
 		)
 		So(err, ShouldBeNil)
 		So(c, ShouldNotBeNil)
@@ -176,3 +190,91 @@ func TestPathEscape(t *testing.T) {
 		So(got, ShouldEqual, want)
 	})
 }
+
+func TestSetBaseURL(t *testing.T) {
+	Convey("test SetBaseURL", t, func() {
+		for _, urlStr := range []string{
+			"https://swarm.url",
+			"https://swarm.url/",
+			"https://swarm.url/api/v9",
+			"https://swarm.url/api/v10/",
+		} {
+			c, err := NewBasicAuthClient("", "", WithBaseURL(urlStr))
+			So(err, ShouldBeNil)
+			So(c.BaseURL().String(), ShouldEqual, "https://swarm.url/")
+		}
+
+		c, err := NewBasicAuthClient("", "", WithBaseURL("https://swarm.url/swarm/api/v9/"))
+		So(err, ShouldBeNil)
+		So(c.BaseURL().String(), ShouldEqual, "https://swarm.url/swarm/")
+
+		req, err := c.NewRequest(http.MethodGet, "projects", nil, nil)
+		So(err, ShouldBeNil)
+		So(req.URL.String(), ShouldEqual, "https://swarm.url/swarm/api/v9/projects")
+
+		req, err = c.NewRequest(http.MethodGet, apiV10Path+"workflows/0", nil, nil)
+		So(err, ShouldBeNil)
+		So(req.URL.String(), ShouldEqual, "https://swarm.url/swarm/api/v10/workflows/0")
+	})
+}
+
+func TestNewRequestDoesNotModifyClient(t *testing.T) {
+	Convey("test NewRequestDoesNotModifyClient", t, func() {
+		c, err := NewBasicAuthClient("", "")
+		So(err, ShouldBeNil)
+
+		_, err = c.NewRequest(http.MethodPut, apiV10Path+"workflows/0", nil, nil)
+		So(err, ShouldBeNil)
+		So(c.BaseURL().String(), ShouldEqual, defaultBaseURL)
+
+		req, err := c.NewRequest(http.MethodGet, "workflows/0", nil, nil)
+		So(err, ShouldBeNil)
+		So(req.URL.String(), ShouldEqual, defaultBaseURL+apiV9Path+"workflows/0")
+	})
+}
+
+func TestConcurrentMixedVersionRequests(t *testing.T) {
+	Convey("test ConcurrentMixedVersionRequests", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/workflows/1", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprint(w, `{"workflow": {"id": 1, "name": "v9"}}`)
+		})
+		mux.HandleFunc("/api/v10/workflows/1", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPut)
+			fmt.Fprint(w, `{"data": {"workflows": [{"id": 1, "name": "v10"}]}}`)
+		})
+
+		var (
+			wg   sync.WaitGroup
+			mu   sync.Mutex
+			errs []error
+		)
+		for i := 0; i < 20; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_, _, err := client.Workflows.GetWorkflow(1)
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}()
+			go func() {
+				defer wg.Done()
+				_, _, err := client.Workflows.putWorkflow("1", &Workflow{ID: 1, Name: "v10"})
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		So(errs, ShouldHaveLength, 40)
+		for _, err := range errs {
+			So(err, ShouldBeNil)
+		}
+		So(client.BaseURL().String(), ShouldEqual, server.URL+"/")
+	})
+}
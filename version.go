@@ -0,0 +1,150 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultAPIVersion is the API version used for paths without API prefix.
+const defaultAPIVersion = "v9"
+
+// apiVersionRegexp matches the API version of a relative API path.
+var apiVersionRegexp = regexp.MustCompile(`^api/(v[0-9.]+)/`)
+
+// Version represents the version information returned by Swarm.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoints.html
+type Version struct {
+	Version     string    `json:"version"`
+	Year        string    `json:"year"`
+	APIVersions []float64 `json:"apiVersions"`
+}
+
+func (v Version) String() string {
+	return Stringify(v)
+}
+
+// Release returns the Swarm release, e.g. 2022.2 for the version string
+// SWARM/2022.2/2341817 (2022/09/26).
+func (v *Version) Release() string {
+	parts := strings.Split(v.Version, "/")
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
+
+// Version gets the version information of the Swarm server.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoints.html
+func (c *Client) Version(options ...RequestOptionFunc) (*Version, *Response, error) {
+	u := apiPrefix + "version"
+
+	req, err := c.NewRequest(http.MethodGet, u, nil, withEndpoint(apiPrefix+"version", options))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	v := new(Version)
+	resp, err := c.Do(req, v)
+	if err != nil {
+		return nil, resp, err
+	}
+
+	return v, resp, err
+}
+
+// VersionCtx is like Version, but runs the request with ctx.
+func (c *Client) VersionCtx(ctx context.Context, options ...RequestOptionFunc) (*Version, *Response, error) {
+	return c.Version(withContextOption(ctx, options)...)
+}
+
+// Capabilities describes the Swarm release and the API versions supported by
+// the Swarm server.
+type Capabilities struct {
+	Release     string
+	Version     string
+	APIVersions []string
+}
+
+// SupportsAPI reports whether the server supports the given API version,
+// e.g. v10.
+func (c *Capabilities) SupportsAPI(version string) bool {
+	for _, v := range c.APIVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// UnsupportedError is returned for requests to an API version which is not
+// supported by the Swarm server.
+type UnsupportedError struct {
+	APIVersion string
+	Path       string
+	Release    string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("%s is not supported by Swarm %s: API %s is not available", e.Path, e.Release, e.APIVersion)
+}
+
+// DiscoverCapabilities gets the version information of the Swarm server and
+// records the capabilities of the server. Once recorded, requests to an API
+// version which is not supported fail with an *UnsupportedError.
+func (c *Client) DiscoverCapabilities(options ...RequestOptionFunc) (*Capabilities, error) {
+	v, _, err := c.Version(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	caps := &Capabilities{
+		Release: v.Release(),
+		Version: v.Version,
+	}
+	for _, version := range v.APIVersions {
+		caps.APIVersions = append(caps.APIVersions, "v"+strconv.FormatFloat(version, 'f', -1, 64))
+	}
+
+	c.capabilitiesLock.Lock()
+	c.capabilities = caps
+	c.capabilitiesLock.Unlock()
+
+	return caps, nil
+}
+
+// Capabilities returns the recorded capabilities of the Swarm server, or nil
+// when they have not been discovered.
+func (c *Client) Capabilities() *Capabilities {
+	c.capabilitiesLock.RLock()
+	defer c.capabilitiesLock.RUnlock()
+	return c.capabilities
+}
+
+// checkSupported returns an *UnsupportedError when the API version of path is
+// known to be unsupported by the Swarm server.
+func (c *Client) checkSupported(path string) error {
+	caps := c.Capabilities()
+	if caps == nil {
+		return nil
+	}
+
+	version := defaultAPIVersion
+	if m := apiVersionRegexp.FindStringSubmatch(path); m != nil {
+		version = m[1]
+	} else if strings.HasPrefix(path, apiPrefix) {
+		// Unversioned API paths are always supported.
+		return nil
+	}
+
+	if !caps.SupportsAPI(version) {
+		return &UnsupportedError{APIVersion: version, Path: path, Release: caps.Release}
+	}
+
+	return nil
+}
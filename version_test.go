@@ -0,0 +1,67 @@
+package swarm
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestClient_Version(t *testing.T) {
+	Convey("test Client_Version", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprint(w, `{"year": "2022", "version": "SWARM/2022.2/2341817 (2022/09/26)", "apiVersions": [1, 1.1, 9, 10]}`)
+		})
+
+		version, _, err := client.Version()
+		So(err, ShouldBeNil)
+		So(version, ShouldResemble, &Version{
+			Version:     "SWARM/2022.2/2341817 (2022/09/26)",
+			Year:        "2022",
+			APIVersions: []float64{1, 1.1, 9, 10},
+		})
+		So(version.Release(), ShouldEqual, "2022.2")
+	})
+}
+
+func TestClient_CapabilityProbe(t *testing.T) {
+	Convey("test Client_CapabilityProbe", t, func() {
+		mux, server, _ := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/version", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprint(w, `{"year": "2019", "version": "SWARM/2019.1/1234567 (2019/03/01)", "apiVersions": [1, 9]}`)
+		})
+		mux.HandleFunc("/api/v9/workflows/0", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			fmt.Fprint(w, `{"workflow": {"id": 0, "name": "Global Workflow"}}`)
+		})
+
+		client, err := NewBasicAuthClient("username", "password", WithBaseURL(server.URL), WithCapabilityProbe())
+		So(err, ShouldBeNil)
+		So(client.Capabilities(), ShouldResemble, &Capabilities{
+			Release:     "2019.1",
+			Version:     "SWARM/2019.1/1234567 (2019/03/01)",
+			APIVersions: []string{"v1", "v9"},
+		})
+
+		_, _, err = client.Workflows.GetWorkflow(0)
+		So(err, ShouldBeNil)
+
+		err = client.Workflows.SetGlobalExclusions([]string{"Admin"}, nil)
+		var unsupported *UnsupportedError
+		So(errors.As(err, &unsupported), ShouldBeTrue)
+		So(unsupported.APIVersion, ShouldEqual, "v10")
+		So(unsupported.Release, ShouldEqual, "2019.1")
+
+		_, err = NewBasicAuthClient("username", "password", WithBaseURL(server.URL+"/missing"), WithCapabilityProbe())
+		So(err, ShouldNotBeNil)
+	})
+}
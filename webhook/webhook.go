@@ -0,0 +1,271 @@
+// Package webhook implements an http.Handler which receives the callbacks
+// Swarm makes for test definitions, deploy hooks and workflow events, and
+// dispatches them to typed handler functions.
+//
+// The URL configured in Swarm must include an event parameter naming the
+// type of the callback, and the shared secret as token parameter, e.g.:
+//
+//	https://ci.url/swarm?event=test&token=secret&review={review}&version={version}&change={change}&status={status}&project={project}&branch={branch}&update={update}
+//
+// All parameters can be sent as query parameters, as URL encoded form body or
+// as a JSON body, except for the token. It must be sent as query parameter or
+// in the X-Swarm-Token header, so the body is only read for authorized
+// callbacks.
+package webhook
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const (
+	// EventParam is the parameter holding the type of the callback.
+	EventParam = "event"
+
+	// TokenParam is the parameter holding the shared secret.
+	TokenParam = "token"
+
+	// TokenHeader is the header which can be used instead of TokenParam.
+	TokenHeader = "X-Swarm-Token"
+
+	// maxBodySize is the maximum size of a callback body.
+	maxBodySize = 1 << 20
+)
+
+// EventType represents the type of a Swarm callback.
+type EventType string
+
+// List of available event types.
+const (
+	EventTest     EventType = "test"
+	EventDeploy   EventType = "deploy"
+	EventWorkflow EventType = "workflow"
+)
+
+// Event represents a callback made by Swarm.
+type Event struct {
+	Type EventType
+
+	// Review, Version and Change identify the review version and the
+	// changelist the callback was triggered for.
+	Review  int
+	Version int
+	Change  int
+
+	// Status is the status of the change (e.g. shelved or submitted).
+	Status string
+
+	// Project and Branch identify the project and branch(es) of the review,
+	// Branch may hold a comma separated list of branch IDs.
+	Project string
+	Branch  string
+
+	// Update, Pass and Fail are the URLs Swarm provides to report results of
+	// a test run or deployment. For deploy callbacks Pass holds the {success}
+	// URL.
+	Update string
+	Pass   string
+	Fail   string
+
+	// Params holds all received parameters, except for the token.
+	Params url.Values
+}
+
+// HandlerFunc handles a single Swarm callback. Returning an error responds
+// with 500 Internal Server Error, the error is logged but not sent to Swarm.
+type HandlerFunc func(ctx context.Context, event *Event) error
+
+// Handler is an http.Handler receiving Swarm callbacks.
+type Handler struct {
+	// ErrorLog is used to log the errors returned by handler functions. If
+	// nil, errors are logged using the standard logger of the log package.
+	ErrorLog *log.Logger
+
+	secret   string
+	handlers map[EventType]HandlerFunc
+}
+
+// NewHandler returns a new Handler which only accepts callbacks carrying the
+// given shared secret. Callbacks are always rejected when secret is empty.
+func NewHandler(secret string) *Handler {
+	return &Handler{
+		secret:   secret,
+		handlers: make(map[EventType]HandlerFunc),
+	}
+}
+
+// Handle registers fn for callbacks of the given event type.
+func (h *Handler) Handle(eventType EventType, fn HandlerFunc) {
+	h.handlers[eventType] = fn
+}
+
+// OnTest registers fn for test definition callbacks.
+func (h *Handler) OnTest(fn HandlerFunc) {
+	h.Handle(EventTest, fn)
+}
+
+// OnDeploy registers fn for deploy callbacks.
+func (h *Handler) OnDeploy(fn HandlerFunc) {
+	h.Handle(EventDeploy, fn)
+}
+
+// OnWorkflow registers fn for workflow event callbacks.
+func (h *Handler) OnWorkflow(fn HandlerFunc) {
+	h.Handle(EventWorkflow, fn)
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	token := r.Header.Get(TokenHeader)
+	if token == "" {
+		token = r.URL.Query().Get(TokenParam)
+	}
+	if !h.verify(token) {
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+		return
+	}
+
+	params, err := parseParams(w, r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params.Del(TokenParam)
+
+	event, err := newEvent(params)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fn, ok := h.handlers[event.Type]
+	if !ok {
+		http.Error(w, fmt.Sprintf("unsupported event %q", event.Type), http.StatusBadRequest)
+		return
+	}
+
+	if err := fn(r.Context(), event); err != nil {
+		h.logf("webhook: %s callback failed: %v", event.Type, err)
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// logf logs an error using ErrorLog, or the standard logger.
+func (h *Handler) logf(format string, args ...interface{}) {
+	if h.ErrorLog != nil {
+		h.ErrorLog.Printf(format, args...)
+		return
+	}
+	log.Printf(format, args...)
+}
+
+// verify reports whether token matches the shared secret.
+func (h *Handler) verify(token string) bool {
+	if h.secret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.secret)) == 1
+}
+
+// parseParams merges the query parameters with the parameters in the body.
+func parseParams(w http.ResponseWriter, r *http.Request) (url.Values, error) {
+	params := r.URL.Query()
+	if r.Method != http.MethodPost || r.Body == nil {
+		return params, nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	body := http.MaxBytesReader(w, r.Body, maxBodySize)
+
+	switch mediaType {
+	case "application/json":
+		// Keep numbers as sent, large change numbers would otherwise be
+		// formatted in exponent form.
+		dec := json.NewDecoder(body)
+		dec.UseNumber()
+		var raw map[string]interface{}
+		if err := dec.Decode(&raw); err != nil {
+			return nil, fmt.Errorf("invalid JSON body: %v", err)
+		}
+		for k, v := range raw {
+			switch v := v.(type) {
+			case nil:
+			case string:
+				params.Set(k, v)
+			case json.Number:
+				params.Set(k, v.String())
+			case []interface{}:
+				for _, item := range v {
+					params.Add(k, fmt.Sprint(item))
+				}
+			default:
+				params.Set(k, fmt.Sprint(v))
+			}
+		}
+
+	case "application/x-www-form-urlencoded":
+		r.Body = body
+		if err := r.ParseForm(); err != nil {
+			return nil, fmt.Errorf("invalid form body: %v", err)
+		}
+		for k, v := range r.PostForm {
+			params[k] = v
+		}
+	}
+
+	return params, nil
+}
+
+// newEvent creates a new Event from the received parameters.
+func newEvent(params url.Values) (*Event, error) {
+	e := &Event{
+		Type:    EventType(strings.ToLower(params.Get(EventParam))),
+		Status:  params.Get("status"),
+		Project: params.Get("project"),
+		Branch:  params.Get("branch"),
+		Update:  params.Get("update"),
+		Pass:    params.Get("pass"),
+		Fail:    params.Get("fail"),
+		Params:  params,
+	}
+	if e.Pass == "" {
+		e.Pass = params.Get("success")
+	}
+	if e.Type == "" {
+		return nil, fmt.Errorf("missing %s parameter", EventParam)
+	}
+
+	for name, field := range map[string]*int{
+		"review":  &e.Review,
+		"version": &e.Version,
+		"change":  &e.Change,
+	} {
+		v := params.Get(name)
+		if v == "" {
+			continue
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s parameter %q", name, v)
+		}
+		*field = n
+	}
+
+	return e, nil
+}
@@ -0,0 +1,146 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	Convey("test Handler_ServeHTTP", t, func() {
+		var received []*Event
+		var logged bytes.Buffer
+		h := NewHandler("secret")
+		h.ErrorLog = log.New(&logged, "", 0)
+		h.OnTest(func(ctx context.Context, e *Event) error {
+			received = append(received, e)
+			return nil
+		})
+		h.OnDeploy(func(ctx context.Context, e *Event) error {
+			return errors.New("deploy failed")
+		})
+
+		Convey("query parameters", func() {
+			r := httptest.NewRequest(http.MethodGet, "/swarm?event=test&token=secret&review=12&version=3&change=1234&status=shelved&project=got-dev&branch=client,server&update=https://swarm.url/update", nil)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			So(w.Code, ShouldEqual, http.StatusNoContent)
+			So(received, ShouldHaveLength, 1)
+			e := received[0]
+			So(e.Type, ShouldEqual, EventTest)
+			So(e.Review, ShouldEqual, 12)
+			So(e.Version, ShouldEqual, 3)
+			So(e.Change, ShouldEqual, 1234)
+			So(e.Status, ShouldEqual, "shelved")
+			So(e.Project, ShouldEqual, "got-dev")
+			So(e.Branch, ShouldEqual, "client,server")
+			So(e.Update, ShouldEqual, "https://swarm.url/update")
+			So(e.Params.Get(TokenParam), ShouldEqual, "")
+		})
+
+		Convey("form body", func() {
+			r := httptest.NewRequest(http.MethodPost, "/swarm?event=test", strings.NewReader("review=7&version=1&pass=https://swarm.url/pass"))
+			r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			r.Header.Set(TokenHeader, "secret")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			So(w.Code, ShouldEqual, http.StatusNoContent)
+			So(received, ShouldHaveLength, 1)
+			So(received[0].Review, ShouldEqual, 7)
+			So(received[0].Pass, ShouldEqual, "https://swarm.url/pass")
+		})
+
+		Convey("JSON body", func() {
+			r := httptest.NewRequest(http.MethodPost, "/swarm?token=secret", strings.NewReader(`{"event": "test", "review": 7, "change": "99", "fail": "https://swarm.url/fail"}`))
+			r.Header.Set("Content-Type", "application/json; charset=utf-8")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			So(w.Code, ShouldEqual, http.StatusNoContent)
+			So(received, ShouldHaveLength, 1)
+			So(received[0].Review, ShouldEqual, 7)
+			So(received[0].Change, ShouldEqual, 99)
+			So(received[0].Fail, ShouldEqual, "https://swarm.url/fail")
+		})
+
+		Convey("JSON body with large numbers", func() {
+			r := httptest.NewRequest(http.MethodPost, "/swarm?token=secret", strings.NewReader(`{"event": "test", "review": 12345678, "change": 123456789}`))
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			So(w.Code, ShouldEqual, http.StatusNoContent)
+			So(received, ShouldHaveLength, 1)
+			So(received[0].Review, ShouldEqual, 12345678)
+			So(received[0].Change, ShouldEqual, 123456789)
+		})
+
+		Convey("invalid requests", func() {
+			for _, tc := range []struct {
+				target string
+				want   int
+			}{
+				{"/swarm?event=test", http.StatusUnauthorized},
+				{"/swarm?event=test&token=wrong", http.StatusUnauthorized},
+				{"/swarm?token=secret", http.StatusBadRequest},
+				{"/swarm?event=workflow&token=secret", http.StatusBadRequest},
+				{"/swarm?event=test&token=secret&review=abc", http.StatusBadRequest},
+				{"/swarm?event=deploy&token=secret", http.StatusInternalServerError},
+			} {
+				w := httptest.NewRecorder()
+				h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tc.target, nil))
+				So(w.Code, ShouldEqual, tc.want)
+			}
+			So(received, ShouldBeEmpty)
+		})
+
+		Convey("unauthorized body is not read", func() {
+			body := &trackingReader{Reader: strings.NewReader(`{"event": "test", "token": "secret"}`)}
+			r := httptest.NewRequest(http.MethodPost, "/swarm", body)
+			r.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			So(w.Code, ShouldEqual, http.StatusUnauthorized)
+			So(body.read, ShouldBeFalse)
+			So(received, ShouldBeEmpty)
+		})
+
+		Convey("handler errors are logged, not returned", func() {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swarm?event=deploy&token=secret", nil))
+			So(w.Code, ShouldEqual, http.StatusInternalServerError)
+			So(w.Body.String(), ShouldNotContainSubstring, "deploy failed")
+			So(logged.String(), ShouldContainSubstring, "deploy failed")
+		})
+
+		Convey("empty secret", func() {
+			h := NewHandler("")
+			h.OnTest(func(ctx context.Context, e *Event) error { return nil })
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/swarm?event=test&token=", nil))
+			So(w.Code, ShouldEqual, http.StatusUnauthorized)
+		})
+	})
+}
+
+// trackingReader records whether the body was read.
+type trackingReader struct {
+	io.Reader
+	read bool
+}
+
+func (r *trackingReader) Read(p []byte) (int, error) {
+	r.read = true
+	return r.Reader.Read(p)
+}
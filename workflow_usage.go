@@ -0,0 +1,197 @@
+package swarm
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+// WorkflowUsage lists the projects and branches using a workflow.
+type WorkflowUsage struct {
+	// Workflow is nil for orphaned workflow IDs, which are used by projects
+	// but do not exist.
+	Workflow *Workflow
+
+	// Projects are the IDs of the projects using the workflow themselves.
+	Projects []string
+
+	// Branches are the branches using the workflow.
+	Branches []BranchMatch
+}
+
+// InUse reports whether any project or branch uses the workflow.
+func (u *WorkflowUsage) InUse() bool {
+	return len(u.Projects) > 0 || len(u.Branches) > 0
+}
+
+// WorkflowUsageReport is the usage of all workflows.
+type WorkflowUsageReport struct {
+	// Usage holds the usage per workflow ID.
+	Usage map[string]*WorkflowUsage
+
+	// Unused are the IDs of workflows not used by any project or branch. The
+	// global workflow is never reported as unused.
+	Unused []string
+
+	// Orphaned are the workflow IDs used by projects or branches which do not
+	// exist.
+	Orphaned []string
+}
+
+// WorkflowInUseError is returned when deleting a workflow which is still
+// used by projects or branches.
+type WorkflowInUseError struct {
+	ID    string
+	Usage *WorkflowUsage
+}
+
+func (e *WorkflowInUseError) Error() string {
+	return fmt.Sprintf("workflow %s is still used by %d project(s) and %d branch(es)", e.ID, len(e.Usage.Projects), len(e.Usage.Branches))
+}
+
+// Usage cross-references all workflows with all projects and reports which
+// projects and branches use each workflow.
+func (s *WorkflowService) Usage(options ...RequestOptionFunc) (*WorkflowUsageReport, error) {
+	workflows, _, err := s.ListWorkflows(nil, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := WithFields(ProjectFieldID, ProjectFieldWorkflow, ProjectFieldBranches)
+	projects, _, err := s.client.Projects.ListProjects(nil, append(append([]RequestOptionFunc{}, options...), fields)...)
+	if err != nil {
+		return nil, err
+	}
+
+	return newWorkflowUsageReport(workflows, projects), nil
+}
+
+// UsageCtx is like Usage, but runs the requests with ctx.
+func (s *WorkflowService) UsageCtx(ctx context.Context, options ...RequestOptionFunc) (*WorkflowUsageReport, error) {
+	return s.Usage(withContextOption(ctx, options)...)
+}
+
+func newWorkflowUsageReport(workflows []*Workflow, projects []*Project) *WorkflowUsageReport {
+	report := &WorkflowUsageReport{Usage: make(map[string]*WorkflowUsage)}
+	for _, w := range workflows {
+		if w != nil {
+			report.Usage[strconv.FormatUint(uint64(w.ID), 10)] = &WorkflowUsage{Workflow: w}
+		}
+	}
+
+	usage := func(id string) *WorkflowUsage {
+		u, ok := report.Usage[id]
+		if !ok {
+			u = &WorkflowUsage{}
+			report.Usage[id] = u
+		}
+		return u
+	}
+	for _, p := range projects {
+		if p == nil {
+			continue
+		}
+		if p.Workflow != "" {
+			u := usage(p.Workflow)
+			u.Projects = append(u.Projects, p.ID)
+		}
+		for i := range p.Branches {
+			if b := &p.Branches[i]; b.Workflow != "" {
+				u := usage(b.Workflow)
+				u.Branches = append(u.Branches, BranchMatch{Project: p, Branch: b})
+			}
+		}
+	}
+
+	for id, u := range report.Usage {
+		switch {
+		case u.Workflow == nil:
+			report.Orphaned = append(report.Orphaned, id)
+		case !u.InUse() && id != "0":
+			report.Unused = append(report.Unused, id)
+		}
+	}
+	sortIDs(report.Unused)
+	sortIDs(report.Orphaned)
+
+	return report
+}
+
+// sortIDs sorts numeric IDs numerically, other IDs after them.
+func sortIDs(ids []string) {
+	sort.Slice(ids, func(i, j int) bool {
+		a, errA := strconv.Atoi(ids[i])
+		b, errB := strconv.Atoi(ids[j])
+		switch {
+		case errA == nil && errB == nil:
+			return a < b
+		case errA == nil || errB == nil:
+			return errA == nil
+		}
+		return ids[i] < ids[j]
+	})
+}
+
+// DeleteWorkflow deletes a workflow. Projects and branches using the workflow
+// are not checked, see SafeDeleteWorkflow.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_workflows.html
+func (s *WorkflowService) DeleteWorkflow(pid interface{}, options ...RequestOptionFunc) (*Response, error) {
+	flowId, err := parseID(pid)
+	if err != nil {
+		return nil, err
+	}
+	u := fmt.Sprintf("workflows/%s", PathEscape(flowId))
+
+	req, err := s.client.NewRequest(http.MethodDelete, u, nil, withEndpoint("workflows/{id}", options))
+	if err != nil {
+		return nil, err
+	}
+
+	return s.client.Do(req, nil)
+}
+
+// DeleteWorkflowCtx is like DeleteWorkflow, but runs the request with ctx.
+func (s *WorkflowService) DeleteWorkflowCtx(ctx context.Context, pid interface{}, options ...RequestOptionFunc) (*Response, error) {
+	return s.DeleteWorkflow(pid, withContextOption(ctx, options)...)
+}
+
+// SafeDeleteWorkflowOptions represents the available SafeDeleteWorkflow()
+// options.
+type SafeDeleteWorkflowOptions struct {
+	// Force deletes the workflow even when it is still in use.
+	Force bool
+}
+
+// SafeDeleteWorkflow deletes a workflow after checking which projects and
+// branches still use it. It fails with a *WorkflowInUseError when the
+// workflow is in use, unless opt.Force is set. The returned usage warns about
+// the projects and branches which used the deleted workflow.
+//
+// Swarm API docs: https://www.perforce.com/manuals/swarm/Content/Swarm/swarm-apidoc_endpoint_workflows.html
+func (s *WorkflowService) SafeDeleteWorkflow(pid interface{}, opt *SafeDeleteWorkflowOptions, options ...RequestOptionFunc) (*WorkflowUsage, *Response, error) {
+	flowId, err := parseID(pid)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report, err := s.Usage(options...)
+	if err != nil {
+		return nil, nil, err
+	}
+	usage := report.Usage[flowId]
+	if usage != nil && usage.InUse() && (opt == nil || !opt.Force) {
+		return usage, nil, &WorkflowInUseError{ID: flowId, Usage: usage}
+	}
+
+	resp, err := s.DeleteWorkflow(flowId, options...)
+	return usage, resp, err
+}
+
+// SafeDeleteWorkflowCtx is like SafeDeleteWorkflow, but runs the requests
+// with ctx.
+func (s *WorkflowService) SafeDeleteWorkflowCtx(ctx context.Context, pid interface{}, opt *SafeDeleteWorkflowOptions, options ...RequestOptionFunc) (*WorkflowUsage, *Response, error) {
+	return s.SafeDeleteWorkflow(pid, opt, withContextOption(ctx, options)...)
+}
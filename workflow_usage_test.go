@@ -0,0 +1,117 @@
+package swarm
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// handleWorkflowUsage serves the workflows and projects used by the usage
+// tests.
+func handleWorkflowUsage(t *testing.T, mux *http.ServeMux) {
+	mux.HandleFunc("/api/v9/workflows", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		fmt.Fprint(w, `{"workflows": [
+			{"id": 0, "name": "Global Workflow"},
+			{"id": 3, "name": "Strict"},
+			{"id": 5, "name": "Relaxed"},
+			{"id": 12, "name": "Legacy"}
+		]}`)
+	})
+	mux.HandleFunc("/api/v9/projects", func(w http.ResponseWriter, r *http.Request) {
+		testMethod(t, r, http.MethodGet)
+		testParams(t, r, "fields=id%2Cworkflow%2Cbranches")
+		fmt.Fprint(w, `{"projects": [
+			{"id": "game", "workflow": "3", "branches": [
+				{"id": "client", "workflow": "5"},
+				{"id": "server", "workflow": "3"}
+			]},
+			{"id": "tools", "workflow": null, "branches": [
+				{"id": "main", "workflow": "7"},
+				{"id": "dev", "workflow": null}
+			]}
+		]}`)
+	})
+}
+
+func TestWorkflowService_Usage(t *testing.T) {
+	Convey("test WorkflowService_Usage", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		handleWorkflowUsage(t, mux)
+
+		report, err := client.Workflows.Usage()
+		So(err, ShouldBeNil)
+		So(report.Unused, ShouldResemble, []string{"12"})
+		So(report.Orphaned, ShouldResemble, []string{"7"})
+
+		strict := report.Usage["3"]
+		So(strict.Workflow.Name, ShouldEqual, "Strict")
+		So(strict.Projects, ShouldResemble, []string{"game"})
+		So(strict.Branches, ShouldHaveLength, 1)
+		So(strict.Branches[0].String(), ShouldEqual, "game/server")
+
+		So(report.Usage["5"].Projects, ShouldBeEmpty)
+		So(report.Usage["5"].Branches[0].String(), ShouldEqual, "game/client")
+		So(report.Usage["7"].Workflow, ShouldBeNil)
+		So(report.Usage["7"].Branches[0].String(), ShouldEqual, "tools/main")
+		So(report.Usage["0"].InUse(), ShouldBeFalse)
+	})
+}
+
+func TestWorkflowService_DeleteWorkflow(t *testing.T) {
+	Convey("test WorkflowService_DeleteWorkflow", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		deleted := false
+		mux.HandleFunc("/api/v9/workflows/3", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodDelete)
+			deleted = true
+			fmt.Fprint(w, `{"isValid": true}`)
+		})
+
+		_, err := client.Workflows.DeleteWorkflow(3)
+		So(err, ShouldBeNil)
+		So(deleted, ShouldBeTrue)
+	})
+}
+
+func TestWorkflowService_SafeDeleteWorkflow(t *testing.T) {
+	Convey("test WorkflowService_SafeDeleteWorkflow", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		handleWorkflowUsage(t, mux)
+		deleted := map[string]bool{}
+		for _, id := range []string{"3", "12"} {
+			id := id
+			mux.HandleFunc("/api/v9/workflows/"+id, func(w http.ResponseWriter, r *http.Request) {
+				testMethod(t, r, http.MethodDelete)
+				deleted[id] = true
+				fmt.Fprint(w, `{"isValid": true}`)
+			})
+		}
+
+		usage, _, err := client.Workflows.SafeDeleteWorkflow(3, nil)
+		var inUse *WorkflowInUseError
+		So(errors.As(err, &inUse), ShouldBeTrue)
+		So(inUse.ID, ShouldEqual, "3")
+		So(inUse.Error(), ShouldEqual, "workflow 3 is still used by 1 project(s) and 1 branch(es)")
+		So(usage.InUse(), ShouldBeTrue)
+		So(deleted["3"], ShouldBeFalse)
+
+		usage, _, err = client.Workflows.SafeDeleteWorkflow(3, &SafeDeleteWorkflowOptions{Force: true})
+		So(err, ShouldBeNil)
+		So(usage.InUse(), ShouldBeTrue)
+		So(deleted["3"], ShouldBeTrue)
+
+		_, _, err = client.Workflows.SafeDeleteWorkflow(12, nil)
+		So(err, ShouldBeNil)
+		So(deleted["12"], ShouldBeTrue)
+	})
+}
@@ -1,18 +1,25 @@
 package swarm
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
-	"github.com/hashicorp/go-retryablehttp"
 	"net/http"
+	"reflect"
+
+	"github.com/hashicorp/go-retryablehttp"
 )
 
 type WorkflowService struct {
 	client *Client
 }
 
+// ListWorkflowsOptions represents the available ListWorkflows() options. Use
+// FieldList or WithFields to build a validated list of fields.
 type ListWorkflowsOptions struct {
-	Fields  *string `query:"fields"`
-	NoCache *string `query:"noCache"`
+	Fields  *string `url:"fields,omitempty" query:"fields"`
+	NoCache *string `url:"noCache,omitempty" query:"noCache"`
 }
 
 type Workflow struct {
@@ -28,6 +35,8 @@ type Workflow struct {
 	CountedVotes   ReviewRule `json:"counted_votes" query:"counted_votes"`
 	GroupExclusion ReviewRule `json:"group_exclusions" query:"group_exclusions"`
 	UserExclusion  ReviewRule `json:"user_exclusions" query:"user_exclusions"`
+	// UserRestrictions is only used by the global workflow.
+	UserRestrictions ReviewRule `json:"user_restrictions" query:"user_restrictions"`
 }
 
 type ReviewRule struct {
@@ -35,6 +44,37 @@ type ReviewRule struct {
 	Mode string      `json:"mode" query:"mode"`
 }
 
+// UnmarshalJSON decodes a ReviewRule, Swarm returns an empty list instead of
+// an object for rules which are not set.
+func (r *ReviewRule) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); bytes.Equal(trimmed, []byte("[]")) || bytes.Equal(trimmed, []byte("null")) {
+		*r = ReviewRule{}
+		return nil
+	}
+
+	type reviewRule ReviewRule
+	return json.Unmarshal(data, (*reviewRule)(r))
+}
+
+// emptyLists returns the exclusion rules which are set to an empty list, so
+// they are sent to Swarm to clear the exclusions.
+func (w *Workflow) emptyLists() []string {
+	var names []string
+	if isEmptyList(w.GroupExclusion.Rule) {
+		names = append(names, "group_exclusions[rule]")
+	}
+	if isEmptyList(w.UserExclusion.Rule) {
+		names = append(names, "user_exclusions[rule]")
+	}
+	return names
+}
+
+// isEmptyList reports whether v is a non-nil, empty slice.
+func isEmptyList(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	return rv.Kind() == reflect.Slice && !rv.IsNil() && rv.Len() == 0
+}
+
 type OnSubmit struct {
 	WithReview    ReviewRule `json:"with_review" query:"with_review"`
 	WithoutReview ReviewRule `json:"without_review" query:"without_review"`
@@ -70,6 +110,13 @@ func (s *WorkflowService) ListWorkflows(opt *ListWorkflowsOptions, options ...Re
 	return r.Workflows, resp, err
 }
 
+// ListWorkflowsCtx is like ListWorkflows, but runs the request with ctx.
+func (s *WorkflowService) ListWorkflowsCtx(ctx context.Context, opt *ListWorkflowsOptions, options ...RequestOptionFunc) ([]*Workflow, *Response, error) {
+	return s.ListWorkflows(opt, withContextOption(ctx, options)...)
+}
+
+// GetWorkflow gets a single workflow. Use WithFields to limit the returned
+// fields.
 func (s *WorkflowService) GetWorkflow(pid interface{}, options ...RequestOptionFunc) (*Workflow, *Response, error) {
 	flowId, err := parseID(pid)
 	if err != nil {
@@ -77,7 +124,7 @@ func (s *WorkflowService) GetWorkflow(pid interface{}, options ...RequestOptionF
 	}
 	u := fmt.Sprintf("workflows/%s", PathEscape(flowId))
 
-	req, err := s.client.NewRequest(http.MethodGet, u, nil, options)
+	req, err := s.client.NewRequest(http.MethodGet, u, nil, withEndpoint("workflows/{id}", options))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -93,50 +140,64 @@ func (s *WorkflowService) GetWorkflow(pid interface{}, options ...RequestOptionF
 	return r.Workflow, resp, err
 }
 
-func (s *WorkflowService) SetGlobalExclusions(groups []string, users []string) (err error) {
+// GetWorkflowCtx is like GetWorkflow, but runs the request with ctx.
+func (s *WorkflowService) GetWorkflowCtx(ctx context.Context, pid interface{}, options ...RequestOptionFunc) (*Workflow, *Response, error) {
+	return s.GetWorkflow(pid, withContextOption(ctx, options)...)
+}
+
+// SetGlobalExclusions replaces the group and user exclusions of the global
+// workflow. The request options are applied to both the read and the update
+// of the global workflow.
+func (s *WorkflowService) SetGlobalExclusions(groups []string, users []string, options ...RequestOptionFunc) (err error) {
 	var workflow *Workflow
-	if workflow, _, err = s.GetWorkflow(0); err != nil {
+	if workflow, _, err = s.GetWorkflow(GlobalWorkflowID, options...); err != nil {
 		return
 	}
+	before := *workflow
 
 	swarmGroups := make([]string, 0)
 	for _, group := range groups {
-		swarmGroups = append(swarmGroups, addPrefix(group, "swarm-group-"))
+		swarmGroups = append(swarmGroups, addPrefix(group, swarmGroupPrefix))
 	}
 	workflow.GroupExclusion.Rule = swarmGroups
 	workflow.UserExclusion.Rule = users
 
-	if err = s.updateWorkflow(0, workflow); err != nil {
-		return
-	}
-
+	_, _, err = s.putWorkflow(fmt.Sprint(GlobalWorkflowID), workflow, withAudit(&before, nil, options)...)
 	return
 }
 
-func (s *WorkflowService) updateWorkflow(pid interface{}, workflow *Workflow) (err error) {
-	var (
-		flowId string
-		req    *retryablehttp.Request
-	)
-	flowId, err = parseID(pid)
-	if err != nil {
-		return
-	}
+// SetGlobalExclusionsCtx is like SetGlobalExclusions, but runs both requests
+// with ctx.
+func (s *WorkflowService) SetGlobalExclusionsCtx(ctx context.Context, groups []string, users []string, options ...RequestOptionFunc) error {
+	return s.SetGlobalExclusions(groups, users, withContextOption(ctx, options)...)
+}
+
+// putWorkflow replaces a workflow using the v10 API and returns the updated
+// workflow.
+func (s *WorkflowService) putWorkflow(flowId string, workflow *Workflow, options ...RequestOptionFunc) (updated *Workflow, resp *Response, err error) {
+	var req *retryablehttp.Request
 	u := fmt.Sprintf(apiV10Path+"workflows/%s", PathEscape(flowId))
-	if len(workflow.Description) <= 0 {
-		workflow.Description = "Updated by v10 api."
-	}
 
-	if req, err = s.client.NewRequest(http.MethodPut, u, workflow, nil); err != nil {
-		return
-	}
-	var r *struct {
+	r := new(struct {
 		Data *struct {
 			Workflows []*Workflow `json:"workflows"`
 		} `json:"data"`
+	})
+	after := func() interface{} {
+		if r.Data == nil || len(r.Data.Workflows) == 0 {
+			return nil
+		}
+		return r.Data.Workflows[0]
 	}
-	if _, err = s.client.Do(req, &r); err != nil {
+
+	if req, err = s.client.NewRequest(http.MethodPut, u, workflow, withEndpoint(apiV10Path+"workflows/{id}", withAudit(nil, after, options))); err != nil {
+		return
+	}
+	if resp, err = s.client.Do(req, r); err != nil {
 		return
 	}
+	if r.Data != nil && len(r.Data.Workflows) > 0 {
+		updated = r.Data.Workflows[0]
+	}
 	return
 }
@@ -1,6 +1,8 @@
 package swarm
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"os"
@@ -308,3 +310,53 @@ func TestWorkflowsService_SetGlobalWorkflow(t *testing.T) {
 		So(err, ShouldBeNil)
 	})
 }
+
+func TestWorkflowsService_SetGlobalExclusionsCtx(t *testing.T) {
+	Convey("test WorkflowsService_SetGlobalExclusionsCtx", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/workflows/0", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodGet)
+			testHeader(t, r, "SUDO", "admin")
+			fmt.Fprint(w, `{"workflow": {"id": 0, "name": "Global Workflow", "description": "global"}}`)
+		})
+		mux.HandleFunc("/api/v10/workflows/0", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPut)
+			testHeader(t, r, "SUDO", "admin")
+			fmt.Fprint(w, `{"data": {"workflows": [{"id": 0, "name": "Global Workflow"}]}}`)
+		})
+
+		err := client.Workflows.SetGlobalExclusionsCtx(context.Background(), []string{"Admin"}, []string{"swarm"}, WithSudo("admin"))
+		So(err, ShouldBeNil)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		err = client.Workflows.SetGlobalExclusionsCtx(ctx, []string{"Admin"}, []string{"swarm"})
+		So(errors.Is(err, context.Canceled), ShouldBeTrue)
+	})
+}
+
+func TestWorkflowsService_SetGlobalExclusionsDescription(t *testing.T) {
+	Convey("test WorkflowsService_SetGlobalExclusions keeps the description", t, func() {
+		mux, server, client := setup(t)
+		defer teardown(server)
+
+		mux.HandleFunc("/api/v9/workflows/0", func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, `{"workflow": {"id": 0, "name": "Global Workflow", "description": ""}}`)
+		})
+		var description []string
+		mux.HandleFunc("/api/v10/workflows/0", func(w http.ResponseWriter, r *http.Request) {
+			testMethod(t, r, http.MethodPut)
+			if err := r.ParseForm(); err != nil {
+				t.Error(err)
+			}
+			description = r.PostForm["description"]
+			fmt.Fprint(w, `{"data": {"workflows": [{"id": 0, "name": "Global Workflow"}]}}`)
+		})
+
+		err := client.Workflows.SetGlobalExclusions([]string{"Admin"}, []string{"swarm"})
+		So(err, ShouldBeNil)
+		So(description, ShouldNotContain, "Updated by v10 api.")
+	})
+}